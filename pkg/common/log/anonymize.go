@@ -0,0 +1,72 @@
+package log
+
+import (
+	"github.com/sirupsen/logrus"
+	"regexp"
+	"sync/atomic"
+)
+
+// userName matches a word of a Windows user name
+const userName = `[^\\/\s"':,;()\[\]<>!?=]+`
+
+var homePaths = []*regexp.Regexp{
+	// C:\Users\alice or C:/Users/John Smith, the name ends at whitespace, punctuation or a path
+	// separator unless the words separated by spaces are followed by a path separator
+	regexp.MustCompile(`(?i)\b([a-z]:[\\/]+Users[\\/]+)(?:` + userName + `(?: ` + userName + `)+([\\/])|` + userName + `)`),
+	// /home/alice, /Users/alice or file:///home/alice
+	regexp.MustCompile(`(^|[\s"'=(:,]|//)(/home/|/Users/)[^/\s"':,;()\[\]<>!?]+`),
+}
+
+var anonymizePaths int32
+
+// EnablePathAnonymization replaces the user name of home directory paths, e.g. C:\Users\alice or
+// /home/alice, with <user> in messages, string field values and errors so logs can be shared without
+// leaking it. A Windows user name with spaces is only recognised as a whole when a path separator
+// follows it. Sinks and the hooks added with AddHook get anonymized entries too
+func EnablePathAnonymization() {
+	atomic.StoreInt32(&anonymizePaths, 1)
+}
+
+// anonymizeEntry returns the entry with home paths anonymized when enabled, the given entry is left
+// untouched as its data may be shared with other entries
+func anonymizeEntry(entry *logrus.Entry) *logrus.Entry {
+	if atomic.LoadInt32(&anonymizePaths) == 0 {
+		return entry
+	}
+	anonymized := *entry
+	anonymized.Message = anonymize(entry.Message)
+	anonymized.Data = make(logrus.Fields, len(entry.Data))
+	for k, v := range entry.Data {
+		switch value := v.(type) {
+		case string:
+			v = anonymize(value)
+		case error:
+			if msg := anonymize(value.Error()); msg != value.Error() {
+				v = &anonymizedError{error: value, msg: msg}
+			}
+		}
+		anonymized.Data[k] = v
+	}
+	return &anonymized
+}
+
+// anonymize replaces the user name of every home directory path in s with <user>
+func anonymize(s string) string {
+	s = homePaths[0].ReplaceAllString(s, "${1}<user>${2}")
+	return homePaths[1].ReplaceAllString(s, "${1}${2}<user>")
+}
+
+// anonymizedError is an error with home paths anonymized in its message
+type anonymizedError struct {
+	error
+	msg string
+}
+
+func (e *anonymizedError) Error() string {
+	return e.msg
+}
+
+// Cause returns the original error
+func (e *anonymizedError) Cause() error {
+	return e.error
+}
@@ -0,0 +1,71 @@
+package log
+
+import (
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync/atomic"
+	"testing"
+)
+
+func TestEnablePathAnonymization(t *testing.T) {
+	_ = Logger()
+	logrus.SetFormatter(&pipelineFormatter{Formatter: &CustomTextFormat{ShowFields: true}})
+	t.Cleanup(func() {
+		atomic.StoreInt32(&anonymizePaths, 0)
+		setFormatter("text")
+	})
+
+	EnablePathAnonymization()
+	out := CaptureOutput(func() {
+		LogFileOp("copy", `C:\Users\alice\AppData\Local\config.json`, nil)
+		LogFileOp("create", "/home/bob/.bashrc", errors.New("open /home/bob/.bashrc: permission denied"))
+	})
+	assert.Equal(t, `INFO: copied C:\Users\<user>\AppData\Local\config.json op=copy path=C:\Users\<user>\AppData\Local\config.json`+"\n"+
+		`ERROR: failed to create /home/<user>/.bashrc: open /home/<user>/.bashrc: permission denied error="open /home/<user>/.bashrc: permission denied" op=create path=/home/<user>/.bashrc`+"\n", out)
+}
+
+func TestEnablePathAnonymizationHooks(t *testing.T) {
+	restoreHooks(t)
+	t.Cleanup(func() { atomic.StoreInt32(&anonymizePaths, 0) })
+	hook := &captureHook{}
+	AddHook("webhook", hook)
+
+	EnablePathAnonymization()
+	_ = CaptureOutput(func() {
+		Logger().WithField("path", `C:\Users\John Smith\.gitconfig`).Info(`reading C:\Users\John Smith\.gitconfig`)
+	})
+	require.Len(t, hook.entries, 1)
+	assert.Equal(t, `reading C:\Users\<user>\.gitconfig`, hook.entries[0].Message)
+	assert.Equal(t, `C:\Users\<user>\.gitconfig`, hook.entries[0].Data["path"])
+}
+
+func Test_anonymize(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{"windows", `C:\Users\alice\Desktop`, `C:\Users\<user>\Desktop`},
+		{"windows forward slashes", "d:/users/alice", "d:/users/<user>"},
+		{"windows home only", `C:\Users\alice`, `C:\Users\<user>`},
+		{"windows name with spaces", `C:\Users\John Smith\Desktop`, `C:\Users\<user>\Desktop`},
+		{"windows quoted", `"C:\Users\alice" missing`, `"C:\Users\<user>" missing`},
+		{"two windows paths", `compare C:\Users\alice and C:\Users\bob\x`, `compare C:\Users\<user> and C:\Users\<user>\x`},
+		{"windows path list", `dir=C:\Users\alice,D:\other`, `dir=C:\Users\<user>,D:\other`},
+		{"windows path and comma", `home is C:\Users\alice, continuing with step 2`, `home is C:\Users\<user>, continuing with step 2`},
+		{"file url", "file:///home/alice/x and file:///C:/Users/bob/y", "file:///home/<user>/x and file:///C:/Users/<user>/y"},
+		{"linux path and comma", "home is /home/bob, continuing", "home is /home/<user>, continuing"},
+		{"linux", "installing to /home/bob/bin", "installing to /home/<user>/bin"},
+		{"mac", "path=/Users/carol/Library", "path=/Users/<user>/Library"},
+		{"several", `"/home/bob" and C:\Users\alice`, `"/home/<user>" and C:\Users\<user>`},
+		{"not a home", "/opt/home/bob and /var/Users/x", "/opt/home/bob and /var/Users/x"},
+		{"no path", "installed git", "installed git"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, anonymize(tt.s))
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	appInsightsEndpoint  = "https://dc.services.visualstudio.com/v2/track"
+	appInsightsQueueSize = 512
+	appInsightsBatchSize = 64
+	// appInsightsFlushTimeout bounds how long Shutdown waits for the queued telemetry to be delivered
+	appInsightsFlushTimeout = 15 * time.Second
+)
+
+// Application Insights severity levels
+const (
+	appInsightsVerbose = iota
+	appInsightsInformation
+	appInsightsWarning
+	appInsightsError
+	appInsightsCritical
+)
+
+var ( // For Test Mocks
+	newAppInsightsTransport = func() appInsightsTransport {
+		return &httpAppInsightsTransport{
+			endpoint: appInsightsEndpoint,
+			client:   &http.Client{Timeout: 10 * time.Second},
+		}
+	}
+)
+
+// appInsightsEnvelope is a single telemetry item in the Application Insights ingestion schema
+type appInsightsEnvelope struct {
+	Name string          `json:"name"`
+	Time string          `json:"time"`
+	IKey string          `json:"iKey"`
+	Data appInsightsData `json:"data"`
+}
+
+type appInsightsData struct {
+	BaseType string      `json:"baseType"`
+	BaseData interface{} `json:"baseData"`
+}
+
+type appInsightsMessageData struct {
+	Ver           int               `json:"ver"`
+	Message       string            `json:"message"`
+	SeverityLevel int               `json:"severityLevel"`
+	Properties    map[string]string `json:"properties,omitempty"`
+}
+
+type appInsightsExceptionData struct {
+	Ver           int                           `json:"ver"`
+	Exceptions    []appInsightsExceptionDetails `json:"exceptions"`
+	SeverityLevel int                           `json:"severityLevel"`
+	Properties    map[string]string             `json:"properties,omitempty"`
+}
+
+type appInsightsExceptionDetails struct {
+	TypeName     string `json:"typeName"`
+	Message      string `json:"message"`
+	HasFullStack bool   `json:"hasFullStack"`
+}
+
+// appInsightsTransport delivers a batch of telemetry items to Application Insights
+type appInsightsTransport interface {
+	Send(items []appInsightsEnvelope) error
+}
+
+type httpAppInsightsTransport struct {
+	endpoint string
+	client   *http.Client
+}
+
+// Send posts the batch to the ingestion endpoint
+func (t *httpAppInsightsTransport) Send(items []appInsightsEnvelope) error {
+	body, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	resp, err := t.client.Post(t.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return errors.Errorf("application insights responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// appInsightsHook is a logrus hook queueing every entry as Application Insights telemetry
+type appInsightsHook struct {
+	name      string
+	iKey      string
+	transport appInsightsTransport
+	queue     chan appInsightsEnvelope
+	// done is closed by run once the queue is closed and drained
+	done   chan struct{}
+	mu     sync.Mutex
+	closed bool
+}
+
+// AddAppInsightsHook registers a hook sending log entries to Application Insights, errors and fatals
+// are sent as exceptions and everything else as traces with the fields as custom properties.
+// Entries are delivered asynchronously, when the queue is full or delivery fails they are dropped.
+// Shutdown delivers the queued entries, waiting up to appInsightsFlushTimeout
+func AddAppInsightsHook(instrumentationKey string) error {
+	if strings.TrimSpace(instrumentationKey) == "" {
+		return errors.New("application insights instrumentation key must not be empty")
+	}
+	hook := &appInsightsHook{
+		name:      "Microsoft.ApplicationInsights." + strings.Replace(instrumentationKey, "-", "", -1),
+		iKey:      instrumentationKey,
+		transport: newAppInsightsTransport(),
+		queue:     make(chan appInsightsEnvelope, appInsightsQueueSize),
+		done:      make(chan struct{}),
+	}
+	go hook.run()
+	onShutdown(func() { hook.flush(appInsightsFlushTimeout) })
+	AddHook("appinsights", hook)
+	return nil
+}
+
+// Levels returns the levels the hook fires for
+func (h *appInsightsHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire queues the entry without blocking the caller, nothing is queued once the hook is flushed
+func (h *appInsightsHook) Fire(entry *logrus.Entry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil
+	}
+	select {
+	case h.queue <- h.envelope(entry):
+	default:
+	}
+	return nil
+}
+
+// flush stops queueing and waits up to the timeout for the queued telemetry to be delivered
+func (h *appInsightsHook) flush(timeout time.Duration) {
+	h.mu.Lock()
+	if !h.closed {
+		h.closed = true
+		close(h.queue)
+	}
+	h.mu.Unlock()
+
+	select {
+	case <-h.done:
+	case <-time.After(timeout):
+	}
+}
+
+// run delivers queued telemetry in batches, ignoring delivery failures, until the queue is closed
+func (h *appInsightsHook) run() {
+	defer close(h.done)
+	for item := range h.queue {
+		batch := []appInsightsEnvelope{item}
+	drain:
+		for len(batch) < appInsightsBatchSize {
+			select {
+			case next, ok := <-h.queue:
+				if !ok {
+					break drain
+				}
+				batch = append(batch, next)
+			default:
+				break drain
+			}
+		}
+		_ = h.transport.Send(batch)
+	}
+}
+
+// envelope converts the entry to a trace, or an exception for error and above
+func (h *appInsightsHook) envelope(entry *logrus.Entry) appInsightsEnvelope {
+	properties := make(map[string]string, len(entry.Data))
+	for k, v := range entry.Data {
+		if k == logrus.ErrorKey {
+			continue
+		}
+		properties[k] = fmt.Sprint(v)
+	}
+	envelope := appInsightsEnvelope{
+		Time: entry.Time.UTC().Format(time.RFC3339Nano),
+		IKey: h.iKey,
+	}
+
+	if entry.Level > logrus.ErrorLevel {
+		envelope.Name = h.name + ".Message"
+		envelope.Data = appInsightsData{
+			BaseType: "MessageData",
+			BaseData: appInsightsMessageData{
+				Ver:           2,
+				Message:       entry.Message,
+				SeverityLevel: appInsightsSeverity(entry.Level),
+				Properties:    properties,
+			},
+		}
+		return envelope
+	}
+
+	details := appInsightsExceptionDetails{TypeName: "error", Message: entry.Message}
+	if err, ok := entry.Data[logrus.ErrorKey].(error); ok {
+		details.TypeName = fmt.Sprintf("%T", err)
+		details.Message = err.Error()
+		properties["message"] = entry.Message
+	}
+	envelope.Name = h.name + ".Exception"
+	envelope.Data = appInsightsData{
+		BaseType: "ExceptionData",
+		BaseData: appInsightsExceptionData{
+			Ver:           2,
+			Exceptions:    []appInsightsExceptionDetails{details},
+			SeverityLevel: appInsightsSeverity(entry.Level),
+			Properties:    properties,
+		},
+	}
+	return envelope
+}
+
+// appInsightsSeverity maps a logrus level to the Application Insights severity level
+func appInsightsSeverity(level logrus.Level) int {
+	switch level {
+	case logrus.PanicLevel, logrus.FatalLevel:
+		return appInsightsCritical
+	case logrus.ErrorLevel:
+		return appInsightsError
+	case logrus.WarnLevel:
+		return appInsightsWarning
+	case logrus.InfoLevel:
+		return appInsightsInformation
+	default:
+		return appInsightsVerbose
+	}
+}
@@ -0,0 +1,103 @@
+package log
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeAppInsightsTransport struct {
+	sent chan appInsightsEnvelope
+}
+
+func (f *fakeAppInsightsTransport) Send(items []appInsightsEnvelope) error {
+	for _, item := range items {
+		f.sent <- item
+	}
+	return errors.New("fake transport never succeeds")
+}
+
+func TestAddAppInsightsHook(t *testing.T) {
+	fake := &fakeAppInsightsTransport{sent: make(chan appInsightsEnvelope, 10)}
+	restoreHooks(t)
+	newTransport := newAppInsightsTransport
+	newAppInsightsTransport = func() appInsightsTransport { return fake }
+	t.Cleanup(func() {
+		newAppInsightsTransport = newTransport
+	})
+
+	require.NoError(t, AddAppInsightsHook("0000-1111"))
+	_ = CaptureOutput(func() {
+		Logger().WithField("step", "install").WithError(errors.New("boom")).Error("install failed")
+		Logger().WithField("step", "install").Info("installing")
+	})
+
+	var items []appInsightsEnvelope
+	for len(items) < 2 {
+		select {
+		case item := <-fake.sent:
+			items = append(items, item)
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for telemetry, got %d items", len(items))
+		}
+	}
+
+	exception := items[0]
+	assert.Equal(t, "Microsoft.ApplicationInsights.00001111.Exception", exception.Name)
+	assert.Equal(t, "0000-1111", exception.IKey)
+	assert.Equal(t, "ExceptionData", exception.Data.BaseType)
+	data := exception.Data.BaseData.(appInsightsExceptionData)
+	assert.Equal(t, appInsightsError, data.SeverityLevel)
+	assert.Equal(t, "boom", data.Exceptions[0].Message)
+	assert.Equal(t, "install", data.Properties["step"])
+	assert.Equal(t, "install failed", data.Properties["message"])
+
+	trace := items[1]
+	assert.Equal(t, "MessageData", trace.Data.BaseType)
+	assert.Equal(t, "installing", trace.Data.BaseData.(appInsightsMessageData).Message)
+}
+
+// slowAppInsightsTransport takes a while to deliver each batch
+type slowAppInsightsTransport struct {
+	mu   sync.Mutex
+	sent []appInsightsEnvelope
+}
+
+func (s *slowAppInsightsTransport) Send(items []appInsightsEnvelope) error {
+	time.Sleep(20 * time.Millisecond)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, items...)
+	return nil
+}
+
+func TestAppInsightsHookFlushedOnShutdown(t *testing.T) {
+	slow := &slowAppInsightsTransport{}
+	restoreHooks(t)
+	restoreShutdown(t)
+	newTransport := newAppInsightsTransport
+	newAppInsightsTransport = func() appInsightsTransport { return slow }
+	t.Cleanup(func() {
+		newAppInsightsTransport = newTransport
+	})
+
+	require.NoError(t, AddAppInsightsHook("0000-1111"))
+	_ = CaptureOutput(func() {
+		for i := 0; i < 3; i++ {
+			Logger().Infof("step %d", i)
+			time.Sleep(time.Millisecond)
+		}
+	})
+	Shutdown()
+
+	slow.mu.Lock()
+	defer slow.mu.Unlock()
+	assert.Len(t, slow.sent, 3)
+}
+
+func TestAddAppInsightsHookEmptyKey(t *testing.T) {
+	assert.Error(t, AddAppInsightsHook(" "))
+}
@@ -0,0 +1,78 @@
+package log
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+var autoJSON = &autoJSONSwitch{json: &logrus.JSONFormatter{}}
+
+// autoJSONSwitch tracks the rate entries are written at per second and switches the text format to
+// JSON while it is above the threshold
+type autoJSONSwitch struct {
+	mu          sync.Mutex
+	threshold   int
+	windowStart time.Time
+	count       int
+	active      bool
+	json        logrus.Formatter
+}
+
+// SetAutoJSONThreshold writes JSON instead of text, which is cheaper to produce, while more than
+// linesPerSec entries are written per second, going back to text once the rate drops. The switches
+// are logged at debug level. 0 disables it
+func SetAutoJSONThreshold(linesPerSec int) {
+	a := autoJSON
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.threshold = linesPerSec
+	a.windowStart = time.Time{}
+	a.count = 0
+	a.active = false
+}
+
+// formatter records the entry and returns the formatter to write it with, along with a notice entry
+// announcing a switch if one happened
+func (a *autoJSONSwitch) formatter(text logrus.Formatter, entry *logrus.Entry) (logrus.Formatter, *logrus.Entry) {
+	if _, ok := text.(*CustomTextFormat); !ok {
+		return text, nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.threshold <= 0 {
+		return text, nil
+	}
+
+	var notice string
+	t := now()
+	if elapsed := t.Sub(a.windowStart); elapsed >= time.Second {
+		if a.active && float64(a.count)/elapsed.Seconds() <= float64(a.threshold) {
+			a.active = false
+			notice = fmt.Sprintf("log rate below %d lines/s, switching back to text output", a.threshold)
+		}
+		a.windowStart = t
+		a.count = 0
+	}
+	a.count++
+	if !a.active && a.count > a.threshold {
+		a.active = true
+		notice = fmt.Sprintf("log rate above %d lines/s, switching to JSON output", a.threshold)
+	}
+
+	formatter := text
+	if a.active {
+		formatter = a.json
+	}
+	if notice == "" || !displayed(logrus.DebugLevel) {
+		return formatter, nil
+	}
+	return formatter, &logrus.Entry{
+		Logger:  entry.Logger,
+		Data:    logrus.Fields{},
+		Time:    entry.Time,
+		Level:   logrus.DebugLevel,
+		Message: notice,
+	}
+}
@@ -0,0 +1,52 @@
+package log
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetAutoJSONThreshold(t *testing.T) {
+	clock := useFakeClock(t)
+	t.Cleanup(func() {
+		SetAutoJSONThreshold(0)
+		_ = SetLevel("info")
+	})
+	setFormatter("text")
+	_ = SetLevel("debug")
+	SetAutoJSONThreshold(3)
+
+	out := CaptureOutput(func() {
+		for i := 0; i < 5; i++ {
+			Logger().WithTime(clock.t).Info("burst")
+			clock.advance(100 * time.Millisecond)
+		}
+		clock.advance(2 * time.Second)
+		Logger().WithTime(clock.t).Info("quiet")
+	})
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	assert.Equal(t, []string{
+		"INFO: burst",
+		"INFO: burst",
+		"INFO: burst",
+		`{"level":"debug","msg":"log rate above 3 lines/s, switching to JSON output","time":"2021-08-26T12:00:00Z"}`,
+		`{"level":"info","msg":"burst","time":"2021-08-26T12:00:00Z"}`,
+		`{"level":"info","msg":"burst","time":"2021-08-26T12:00:00Z"}`,
+		"DEBUG: log rate below 3 lines/s, switching back to text output",
+		"INFO: quiet",
+	}, lines)
+}
+
+func TestSetAutoJSONThresholdDisabled(t *testing.T) {
+	useFakeClock(t)
+	setFormatter("text")
+	SetAutoJSONThreshold(0)
+
+	out := CaptureOutput(func() {
+		for i := 0; i < 10; i++ {
+			Logger().Info("burst")
+		}
+	})
+	assert.Equal(t, strings.Repeat("INFO: burst\n", 10), out)
+}
@@ -0,0 +1,98 @@
+package log
+
+import (
+	"github.com/sirupsen/logrus"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+var ( // For Test Mocks
+	hostname = os.Hostname
+)
+
+var (
+	versionMu sync.RWMutex
+	version   string
+)
+
+// SetVersion sets the version shown by LogBanner
+func SetVersion(v string) {
+	versionMu.Lock()
+	defer versionMu.Unlock()
+	version = v
+}
+
+// LogBanner logs a boxed banner at info level with the title above the version, hostname, OS and start
+// time, sized to the longest line. The version and hostname are attached as fields too
+func LogBanner(title string) {
+	versionMu.RLock()
+	v := version
+	versionMu.RUnlock()
+	host, _ := hostname()
+
+	fields := logrus.Fields{"hostname": host}
+	var details []string
+	if v != "" {
+		fields["version"] = v
+		details = append(details, "version: "+v)
+	}
+	details = append(details,
+		"host:    "+host,
+		"os:      "+runtime.GOOS+"/"+runtime.GOARCH,
+		"started: "+now().Format("2006-01-02 15:04:05"),
+	)
+	withStyle(Logger().WithFields(fields), bannerStyle(title)).Info(renderBanner(title, details))
+}
+
+// renderBanner draws a box around the title and the detail lines, starting with a newline so the box
+// is not shifted by the level prefix
+func renderBanner(title string, details []string) string {
+	width := utf8.RuneCountInString(title)
+	for _, line := range details {
+		if n := utf8.RuneCountInString(line); n > width {
+			width = n
+		}
+	}
+	row := func(s string) string {
+		return "│ " + s + strings.Repeat(" ", width-utf8.RuneCountInString(s)) + " │\n"
+	}
+	rule := strings.Repeat("─", width+2)
+
+	var b strings.Builder
+	b.WriteString("\n╭" + rule + "╮\n")
+	b.WriteString(row(title))
+	b.WriteString("├" + rule + "┤\n")
+	for _, line := range details {
+		b.WriteString(row(line))
+	}
+	b.WriteString("╰" + rule + "╯")
+	return b.String()
+}
+
+// bannerStyle colors a banner rendered by renderBanner, the borders in the status color and the title
+// in the info color
+func bannerStyle(title string) messageStyle {
+	return func(msg string) string {
+		lines := strings.Split(msg, "\n")
+		titleRow := true
+		for i, line := range lines {
+			if line == "" {
+				continue
+			}
+			if !strings.HasPrefix(line, "│") {
+				lines[i] = colorStatus(line)
+				continue
+			}
+			inner := strings.TrimSuffix(strings.TrimPrefix(line, "│"), "│")
+			if titleRow && strings.HasPrefix(inner, " "+title) {
+				inner = " " + colorInfo(title) + strings.TrimPrefix(inner, " "+title)
+			}
+			titleRow = false
+			lines[i] = colorStatus("│") + inner + colorStatus("│")
+		}
+		return strings.Join(lines, "\n")
+	}
+}
@@ -0,0 +1,52 @@
+package log
+
+import (
+	"github.com/Benbentwo/Windows10BootStrapper/pkg/common/log/logtest"
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestLogBanner(t *testing.T) {
+	useFakeClock(t)
+	hostnameFunc := hostname
+	hostname = func() (string, error) { return "build-01", nil }
+	t.Cleanup(func() {
+		hostname = hostnameFunc
+		SetVersion("")
+	})
+	setFormatter("text")
+	SetVersion("1.2.3")
+
+	out := CaptureOutput(func() { LogBanner("Windows 10 Bootstrapper") })
+	osLine := "os:      " + runtime.GOOS + "/" + runtime.GOARCH
+	want := []string{
+		"INFO: ",
+		"╭──────────────────────────────╮",
+		"│ Windows 10 Bootstrapper      │",
+		"├──────────────────────────────┤",
+		"│ version: 1.2.3               │",
+		"│ host:    build-01            │",
+		"│ " + osLine + strings.Repeat(" ", 28-len(osLine)) + " │",
+		"│ started: 2021-08-26 12:00:00 │",
+		"╰──────────────────────────────╯",
+	}
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	assert.Equal(t, want, lines)
+	entries := CaptureEntries(func() { LogBanner("setup") })
+	logtest.AssertFieldValue(t, entries, "version", "1.2.3")
+	logtest.AssertFieldValue(t, entries, "hostname", "build-01")
+}
+
+func TestRenderBannerColor(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	t.Cleanup(func() { color.NoColor = noColor })
+
+	banner := bannerStyle("setup")(renderBanner("setup", []string{"os: test"}))
+	assert.Contains(t, banner, colorStatus("│")+" "+colorInfo("setup")+"    "+colorStatus("│"))
+	assert.Contains(t, banner, colorStatus("│")+" os: test "+colorStatus("│"))
+	assert.Contains(t, banner, colorStatus("╰──────────╯"))
+}
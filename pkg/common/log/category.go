@@ -0,0 +1,39 @@
+package log
+
+import (
+	"github.com/fatih/color"
+	"sync"
+)
+
+// CategoryField is the field holding the category of an entry e.g. network, disk or registry
+const CategoryField = "category"
+
+var (
+	categoryColorsMu sync.RWMutex
+	categoryColors   = map[string]color.Attribute{}
+)
+
+// SetCategoryColor sets the color of the "[category]" prefix rendered by the text format
+func SetCategoryColor(category string, attr color.Attribute) {
+	categoryColorsMu.Lock()
+	defer categoryColorsMu.Unlock()
+	categoryColors[category] = attr
+}
+
+// LogCategory logs msg at info level under the given category, rendered with a colored "[category]"
+// prefix in text format and as the category field in JSON
+func LogCategory(category string, msg string) {
+	Logger().WithField(CategoryField, category).Info(msg)
+}
+
+// colorCategory renders the "[category]" prefix in the color set for the category, uncolored if none was set
+func colorCategory(category string) string {
+	prefix := "[" + category + "]"
+	categoryColorsMu.RLock()
+	attr, ok := categoryColors[category]
+	categoryColorsMu.RUnlock()
+	if !ok {
+		return prefix
+	}
+	return color.New(attr).Sprint(prefix)
+}
@@ -0,0 +1,37 @@
+package log
+
+import (
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLogCategory(t *testing.T) {
+	setFormatter("text")
+	noColor := color.NoColor
+	color.NoColor = false
+	t.Cleanup(func() { color.NoColor = noColor })
+
+	SetCategoryColor("network", color.FgBlue)
+	SetCategoryColor("disk", color.FgMagenta)
+
+	network := CaptureOutput(func() { LogCategory("network", "resolving mirror") })
+	disk := CaptureOutput(func() { LogCategory("disk", "checking free space") })
+	other := CaptureOutput(func() { LogCategory("registry", "reading key") })
+
+	networkPrefix := color.New(color.FgBlue).Sprint("[network]")
+	diskPrefix := color.New(color.FgMagenta).Sprint("[disk]")
+	assert.Contains(t, network, "\x1b[34m[network]")
+	assert.Contains(t, disk, "\x1b[35m[disk]")
+	assert.Equal(t, colorInfo("INFO")+": "+networkPrefix+" resolving mirror\n", network)
+	assert.Equal(t, colorInfo("INFO")+": "+diskPrefix+" checking free space\n", disk)
+	assert.Equal(t, colorInfo("INFO")+": [registry] reading key\n", other)
+}
+
+func TestLogCategoryJSON(t *testing.T) {
+	setFormatter("json")
+	t.Cleanup(func() { setFormatter("text") })
+	out := CaptureOutput(func() { LogCategory("network", "resolving mirror") })
+	assert.Contains(t, out, `"category":"network"`)
+	assert.Contains(t, out, `"msg":"resolving mirror"`)
+}
@@ -0,0 +1,66 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Kinds of changes summarised by LogChangeSummary with their own wording, any other kind is summarised
+// as "N <kind> changes"
+const (
+	ChangeInstall  = "install"
+	ChangeRegistry = "registry"
+	ChangeFile     = "file"
+)
+
+var changeWording = map[string]struct{ verb, singular, plural string }{
+	ChangeInstall:  {"installed", "package", "packages"},
+	ChangeRegistry: {"modified", "registry key", "registry keys"},
+	ChangeFile:     {"wrote", "file", "files"},
+}
+
+var (
+	changesMu   sync.Mutex
+	changeKinds []string
+	changes     = map[string]map[string]bool{}
+)
+
+// RecordChange records a change made to the system for the summary logged by LogChangeSummary, e.g.
+// RecordChange(ChangeInstall, "git"). Recording the same target twice counts it once
+func RecordChange(kind, target string) {
+	changesMu.Lock()
+	defer changesMu.Unlock()
+	targets, ok := changes[kind]
+	if !ok {
+		targets = map[string]bool{}
+		changes[kind] = targets
+		changeKinds = append(changeKinds, kind)
+	}
+	targets[target] = true
+}
+
+// LogChangeSummary logs the recorded changes grouped by kind at info level, in the order the kinds were
+// first recorded, e.g. "Installed 3 packages, modified 2 registry keys", with the counts as the changes field
+func LogChangeSummary() {
+	changesMu.Lock()
+	counts := make(map[string]int, len(changes))
+	parts := make([]string, 0, len(changeKinds))
+	for _, kind := range changeKinds {
+		n := len(changes[kind])
+		counts[kind] = n
+		if wording, ok := changeWording[kind]; ok {
+			parts = append(parts, fmt.Sprintf("%s %d %s", wording.verb, n, pluralize(n, wording.singular, wording.plural)))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d %s %s", n, kind, pluralize(n, "change", "changes")))
+		}
+	}
+	changesMu.Unlock()
+
+	if len(parts) == 0 {
+		Logger().Info("No changes made")
+		return
+	}
+	summary := strings.Join(parts, ", ")
+	Logger().WithField("changes", counts).Info(strings.ToUpper(summary[:1]) + summary[1:])
+}
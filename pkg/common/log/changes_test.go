@@ -0,0 +1,43 @@
+package log
+
+import (
+	"github.com/Benbentwo/Windows10BootStrapper/pkg/common/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// resetChanges forgets the recorded changes once the test finishes
+func resetChanges(t *testing.T) {
+	t.Cleanup(func() {
+		changeKinds = nil
+		changes = map[string]map[string]bool{}
+	})
+}
+
+func TestLogChangeSummary(t *testing.T) {
+	setFormatter("text")
+	resetChanges(t)
+
+	out := CaptureOutput(LogChangeSummary)
+	assert.Equal(t, "INFO: No changes made\n", out)
+
+	RecordChange(ChangeInstall, "git")
+	RecordChange(ChangeRegistry, `HKCU\Software\Classes\.md`)
+	RecordChange(ChangeInstall, "vscode")
+	RecordChange(ChangeInstall, "git")
+	RecordChange("service", "sshd")
+	RecordChange(ChangeInstall, "wsl")
+	RecordChange(ChangeRegistry, `HKLM\SYSTEM\CurrentControlSet\Control\FileSystem`)
+	RecordChange(ChangeFile, `C:\Users\me\.gitconfig`)
+
+	out = CaptureOutput(LogChangeSummary)
+	assert.Equal(t, "INFO: Installed 3 packages, modified 2 registry keys, 1 service change, wrote 1 file\n", out)
+
+	entries := CaptureEntries(LogChangeSummary)
+	logtest.AssertFieldValue(t, entries, "changes", map[string]int{
+		ChangeInstall:  3,
+		ChangeRegistry: 2,
+		ChangeFile:     1,
+		"service":      1,
+	})
+}
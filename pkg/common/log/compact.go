@@ -0,0 +1,68 @@
+package log
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"strings"
+	"sync"
+)
+
+var compact = &compactRepeats{}
+
+// compactRepeats holds whether repeated prefixes are compacted, the generation changes with every
+// SetCompactRepeats so the text formatters forget the previous line
+type compactRepeats struct {
+	mu         sync.Mutex
+	on         bool
+	generation int
+}
+
+// compactState remembers the prefix of the previous line written by a text formatter, each output
+// has its own formatter so lines are compared with those of the same output only
+type compactState struct {
+	mu         sync.Mutex
+	generation int
+	last       string
+}
+
+// SetCompactRepeats renders the level prefix of the text format only on the first of consecutive lines
+// with the same level and sub-command, the following lines are indented to align with it
+func SetCompactRepeats(on bool) {
+	compact.mu.Lock()
+	defer compact.mu.Unlock()
+	compact.on = on
+	compact.generation++
+}
+
+// repeatsPrefix reports whether the entry has the same prefix as the previous line of the state,
+// remembering it for the next
+func repeatsPrefix(state *compactState, entry *logrus.Entry) bool {
+	compact.mu.Lock()
+	on, generation := compact.on, compact.generation
+	compact.mu.Unlock()
+	if !on {
+		return false
+	}
+
+	key := entry.Level.String()
+	if subCommand, ok := entry.Data[SubCommandField]; ok {
+		key += " " + fmt.Sprint(subCommand)
+	}
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.generation != generation {
+		state.generation = generation
+		state.last = ""
+	}
+	repeated := state.last == key
+	state.last = key
+	return repeated
+}
+
+// blankPrefix returns the spaces aligning a line with the rendered prefix of the label
+func blankPrefix(label string) string {
+	if levelBadgesEnabled() {
+		return strings.Repeat(" ", badgeWidth+3)
+	}
+	return strings.Repeat(" ", len(label)+2)
+}
@@ -0,0 +1,71 @@
+package log
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetCompactRepeats(t *testing.T) {
+	setFormatter("text")
+	SetCompactRepeats(true)
+	t.Cleanup(func() { SetCompactRepeats(false) })
+
+	out := CaptureOutput(func() {
+		Logger().Info("downloading")
+		Logger().Info("verifying")
+		Logger().Info("installing")
+		Logger().Warn("reboot required")
+		Logger().Warn("reboot skipped")
+		PushSubCommand("deploy")
+		Logger().Warn("deploying")
+		PopSubCommand()
+	})
+	assert.Equal(t, "INFO: downloading\n"+
+		"      verifying\n"+
+		"      installing\n"+
+		"WARNING: reboot required\n"+
+		"         reboot skipped\n"+
+		"WARNING: deploying\n", out)
+}
+
+func TestSetCompactRepeatsBadges(t *testing.T) {
+	setFormatter("text")
+	SetCompactRepeats(true)
+	SetLevelBadges(true)
+	t.Cleanup(func() {
+		SetCompactRepeats(false)
+		SetLevelBadges(false)
+	})
+
+	out := CaptureOutput(func() {
+		Logger().Info("downloading")
+		Logger().Info("verifying")
+	})
+	assert.Equal(t, " INFO     downloading\n          verifying\n", out)
+}
+
+func TestSetCompactRepeatsOff(t *testing.T) {
+	setFormatter("text")
+	out := CaptureOutput(func() {
+		Logger().Info("downloading")
+		Logger().Info("verifying")
+	})
+	assert.Equal(t, "INFO: downloading\nINFO: verifying\n", out)
+}
+
+func TestSetCompactRepeatsPerOutput(t *testing.T) {
+	restoreHooks(t)
+	setFormatter("text")
+	SetCompactRepeats(true)
+	t.Cleanup(func() { SetCompactRepeats(false) })
+
+	audit := &bytes.Buffer{}
+	AddSink("audit", audit, "text")
+	out := CaptureOutput(func() {
+		Logger().Info("downloading")
+		Logger().Info("verifying")
+	})
+	assert.Equal(t, "INFO: downloading\n      verifying\n", out)
+	assert.Equal(t, out, audit.String())
+}
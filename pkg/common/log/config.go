@@ -0,0 +1,229 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/fatih/color"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// configDirName is the directory below the user config directory holding the default config file
+const configDirName = "Windows10BootStrapper"
+
+// configDir holds the default config file, empty when the user config directory is unknown
+var configDir = defaultConfigDir()
+
+// defaultConfigDir returns the package directory below the user config directory
+func defaultConfigDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, configDirName)
+}
+
+var (
+	configSinksMu sync.Mutex
+	configSinks   []configSink
+)
+
+// configSink is a sink added by a config file, replaced when another config is applied
+type configSink struct {
+	hook *sinkHook
+	file *os.File
+}
+
+// Config is the logging configuration read from a log.json or log.yaml file
+type Config struct {
+	Level      string       `json:"level" yaml:"level"`
+	Format     string       `json:"format" yaml:"format"`
+	Timestamps *bool        `json:"timestamps" yaml:"timestamps"`
+	Color      *bool        `json:"color" yaml:"color"`
+	Fields     *bool        `json:"fields" yaml:"fields"`
+	Sinks      []SinkConfig `json:"sinks" yaml:"sinks"`
+}
+
+// SinkConfig is an additional file every entry is appended to
+type SinkConfig struct {
+	Name   string `json:"name" yaml:"name"`
+	Path   string `json:"path" yaml:"path"`
+	Format string `json:"format" yaml:"format"`
+}
+
+// LoadConfigFile reads the JSON or YAML config file, chosen by its extension, and applies it to the
+// default logger. Nothing is applied when the file is invalid, the returned error lists every problem.
+// The LOG_FORMAT environment variable takes precedence over the format in the file
+func LoadConfigFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "reading log config %s", path)
+	}
+
+	var cfg Config
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&cfg)
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&cfg)
+	default:
+		return errors.Errorf("unsupported log config %s, use a .json or .yaml file", path)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "parsing log config %s", path)
+	}
+
+	if problems := cfg.validate(); len(problems) > 0 {
+		return errors.Errorf("invalid log config %s: %s", path, strings.Join(problems, "; "))
+	}
+	return cfg.apply()
+}
+
+// loadDefaultConfig applies the first log.json, log.yaml or log.yml found in the user config directory
+func loadDefaultConfig() error {
+	if configDir == "" {
+		return nil
+	}
+	for _, name := range []string{"log.json", "log.yaml", "log.yml"} {
+		path := filepath.Join(configDir, name)
+		if _, err := os.Stat(path); err == nil {
+			return LoadConfigFile(path)
+		}
+	}
+	return nil
+}
+
+// validate returns a description of every invalid setting
+func (c *Config) validate() []string {
+	var problems []string
+	if c.Level != "" {
+		if _, err := logrus.ParseLevel(c.Level); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid level '%s'", c.Level))
+		}
+	}
+	if !validFormat(c.Format) {
+		problems = append(problems, fmt.Sprintf("invalid format '%s'", c.Format))
+	}
+	for i, sink := range c.Sinks {
+		if sink.Path == "" {
+			problems = append(problems, fmt.Sprintf("sink %d has no path", i))
+		}
+		if !validFormat(sink.Format) {
+			problems = append(problems, fmt.Sprintf("sink %d has invalid format '%s'", i, sink.Format))
+		}
+	}
+	return problems
+}
+
+func validFormat(format string) bool {
+	switch format {
+	case "", "text", "json", "json-wrapped", "proto":
+		return true
+	}
+	return false
+}
+
+// apply configures the default logger, the config must be valid. The sink files are opened first so
+// nothing is applied when one cannot be, the sinks of the previously applied config are then replaced
+// and their files closed
+func (c *Config) apply() error {
+	files, err := c.openSinks()
+	if err != nil {
+		return err
+	}
+
+	if c.Level != "" {
+		if err := SetLevel(c.Level); err != nil {
+			for _, f := range files {
+				closeFile(f)
+			}
+			return err
+		}
+	}
+	if c.Format != "" || c.Timestamps != nil {
+		layout := currentLayout()
+		if c.Format != "" && os.Getenv("LOG_FORMAT") == "" {
+			layout = FormatLayoutType(c.Format)
+		}
+		installFormatter(layout, c.formatter(layout))
+	}
+	if c.Color != nil {
+		color.NoColor = !*c.Color
+	}
+	if c.Fields != nil {
+		SetShowFields(*c.Fields)
+	}
+
+	configSinksMu.Lock()
+	defer configSinksMu.Unlock()
+	replaced := map[*sinkHook]bool{}
+	for _, sink := range configSinks {
+		replaced[sink.hook] = true
+	}
+	removeSinks(func(sink *sinkHook) bool {
+		return replaced[sink]
+	})
+	for _, sink := range configSinks {
+		closeFile(sink.file)
+	}
+	configSinks = nil
+	for i, sink := range c.Sinks {
+		name := sink.Name
+		if name == "" {
+			name = sink.Path
+		}
+		hook := newSinkHook(name, files[i], FormatLayoutType(sink.Format))
+		configSinks = append(configSinks, configSink{hook: hook, file: files[i]})
+		logrus.AddHook(hook)
+	}
+	return nil
+}
+
+// openSinks opens the file of every sink, the files already opened are closed when one fails
+func (c *Config) openSinks() ([]*os.File, error) {
+	var files []*os.File
+	for _, sink := range c.Sinks {
+		f, err := os.OpenFile(sink.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			for _, opened := range files {
+				closeFile(opened)
+			}
+			return nil, errors.Wrapf(err, "opening log sink %s", sink.Path)
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// closeFile closes a sink file
+func closeFile(f *os.File) {
+	if err := f.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to close log sink %s, %v\n", f.Name(), err)
+	}
+}
+
+// formatter returns a new formatter for the layout with the timestamps of the config, the formatter in
+// use is never changed in place as entries may be formatted with it concurrently
+func (c *Config) formatter(layout FormatLayoutType) logrus.Formatter {
+	formatter := newFormatter(layout)
+	if c.Timestamps != nil {
+		switch f := formatter.(type) {
+		case *CustomTextFormat:
+			f.ShowTimestamp = *c.Timestamps
+		case *logrus.JSONFormatter:
+			f.DisableTimestamp = !*c.Timestamps
+		}
+	}
+	return formatter
+}
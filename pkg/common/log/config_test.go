@@ -0,0 +1,147 @@
+package log
+
+import (
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// restoreConfig restores the settings a config file changes once the test finishes
+func restoreConfig(t *testing.T) {
+	restoreHooks(t)
+	noColor := color.NoColor
+	t.Cleanup(func() {
+		configSinksMu.Lock()
+		for _, sink := range configSinks {
+			closeFile(sink.file)
+		}
+		configSinks = nil
+		configSinksMu.Unlock()
+		color.NoColor = noColor
+		SetShowFields(false)
+		_ = SetLevel("info")
+		setFormatter("text")
+	})
+}
+
+func writeConfig(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	restoreConfig(t)
+	audit := filepath.Join(t.TempDir(), "audit.log")
+	path := writeConfig(t, "log.json", `{
+		"level": "debug",
+		"format": "text",
+		"timestamps": true,
+		"color": true,
+		"sinks": [{"name": "audit", "path": "`+filepath.ToSlash(audit)+`", "format": "json"}]
+	}`)
+
+	require.NoError(t, LoadConfigFile(path))
+	assert.Equal(t, logrus.DebugLevel, logrus.GetLevel())
+	assert.True(t, baseFormatter().(*CustomTextFormat).ShowTimestamp)
+	assert.False(t, color.NoColor)
+	assert.Equal(t, []string{"stderr", "audit"}, ActiveSinks())
+
+	CaptureOutput(func() { Logger().Debug("configured") })
+	written, err := ioutil.ReadFile(audit)
+	require.NoError(t, err)
+	assert.Contains(t, string(written), `"msg":"configured"`)
+}
+
+func TestLoadConfigFileReload(t *testing.T) {
+	restoreConfig(t)
+	dir := t.TempDir()
+	sinks := func(names ...string) string {
+		var b strings.Builder
+		for i, name := range names {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			b.WriteString(`{"name": "` + name + `", "path": "` + filepath.ToSlash(filepath.Join(dir, name+".log")) + `"}`)
+		}
+		return `{"sinks": [` + b.String() + `]}`
+	}
+
+	require.NoError(t, LoadConfigFile(writeConfig(t, "log.json", sinks("audit"))))
+	configSinksMu.Lock()
+	first := configSinks[0].file
+	configSinksMu.Unlock()
+	require.NoError(t, LoadConfigFile(writeConfig(t, "log.json", sinks("audit", "trace"))))
+	assert.Equal(t, []string{"stderr", "audit", "trace"}, ActiveSinks())
+	assert.Error(t, first.Close(), "the file of the replaced sink is closed")
+
+	err := LoadConfigFile(writeConfig(t, "log.json", `{"level": "debug", "sinks": [{"path": "`+filepath.ToSlash(dir)+`"}]}`))
+	assert.Error(t, err)
+	assert.Equal(t, logrus.InfoLevel, logrus.GetLevel())
+	assert.Equal(t, []string{"stderr", "audit", "trace"}, ActiveSinks())
+}
+
+func TestLoadConfigFileKeepsFormatter(t *testing.T) {
+	restoreConfig(t)
+	setFormatter("text")
+	before := baseFormatter().(*CustomTextFormat)
+
+	require.NoError(t, LoadConfigFile(writeConfig(t, "log.json", `{"timestamps": true}`)))
+	assert.False(t, before.ShowTimestamp)
+	assert.True(t, baseFormatter().(*CustomTextFormat).ShowTimestamp)
+}
+
+func TestLoadConfigFileYAML(t *testing.T) {
+	restoreConfig(t)
+	path := writeConfig(t, "log.yaml", "level: warn\nformat: json\ntimestamps: false\ncolor: false\nfields: true\n")
+
+	require.NoError(t, LoadConfigFile(path))
+	assert.Equal(t, logrus.WarnLevel, logrus.GetLevel())
+	assert.True(t, baseFormatter().(*logrus.JSONFormatter).DisableTimestamp)
+	assert.True(t, color.NoColor)
+	assert.True(t, showFieldsEnabled())
+}
+
+func TestLoadConfigFileInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    string
+	}{
+		{"invalid values", "log.json", `{"level": "loud", "format": "xml", "sinks": [{"format": "csv"}]}`,
+			"invalid level 'loud'; invalid format 'xml'; sink 0 has no path; sink 0 has invalid format 'csv'"},
+		{"unknown setting", "log.json", `{"colour": true}`, `unknown field "colour"`},
+		{"unknown yaml setting", "log.yml", "colour: true\n", "field colour not found"},
+		{"unsupported extension", "log.toml", "", "use a .json or .yaml file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restoreConfig(t)
+			_ = SetLevel("info")
+			err := LoadConfigFile(writeConfig(t, tt.file, tt.content))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+			assert.Equal(t, logrus.InfoLevel, logrus.GetLevel())
+		})
+	}
+}
+
+func TestLoadDefaultConfig(t *testing.T) {
+	restoreConfig(t)
+	dir := configDir
+	configDir = t.TempDir()
+	t.Cleanup(func() { configDir = dir })
+
+	require.NoError(t, loadDefaultConfig())
+	assert.Equal(t, logrus.InfoLevel, logrus.GetLevel())
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(configDir, "log.yaml"), []byte("level: debug\n"), 0644))
+	require.NoError(t, loadDefaultConfig())
+	assert.Equal(t, logrus.DebugLevel, logrus.GetLevel())
+}
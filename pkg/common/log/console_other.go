@@ -0,0 +1,6 @@
+// +build !windows
+
+package log
+
+// enableVirtualTerminal is a no-op, terminals outside of windows interpret ansi escapes natively
+func enableVirtualTerminal() {}
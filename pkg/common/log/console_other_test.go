@@ -0,0 +1,19 @@
+// +build !windows
+
+package log
+
+import (
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEnableVirtualTerminal(t *testing.T) {
+	for _, noColor := range []bool{true, false} {
+		previous := color.NoColor
+		color.NoColor = noColor
+		enableVirtualTerminal()
+		assert.Equal(t, noColor, color.NoColor)
+		color.NoColor = previous
+	}
+}
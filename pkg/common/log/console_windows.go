@@ -0,0 +1,39 @@
+// +build windows
+
+package log
+
+import (
+	"github.com/fatih/color"
+	"golang.org/x/sys/windows"
+	"os"
+)
+
+func init() {
+	enableVirtualTerminal()
+}
+
+// enableVirtualTerminal enables virtual terminal processing on the console so the ansi escape sequences
+// used for colors are rendered, requires windows 10 1511 or higher and falls back to uncolored output
+// on older consoles
+// https://docs.microsoft.com/en-us/windows/console/console-virtual-terminal-sequences
+func enableVirtualTerminal() {
+	for _, f := range []*os.File{os.Stderr, os.Stdout} {
+		if !enableVirtualTerminalProcessing(f) {
+			color.NoColor = true
+		}
+	}
+}
+
+// enableVirtualTerminalProcessing returns false only if f is a console which cannot interpret ansi escapes
+func enableVirtualTerminalProcessing(f *os.File) bool {
+	handle := windows.Handle(f.Fd())
+	var mode uint32
+	if err := windows.GetConsoleMode(handle, &mode); err != nil {
+		// not a console e.g. redirected to a file, colors are already disabled there
+		return true
+	}
+	if mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 {
+		return true
+	}
+	return windows.SetConsoleMode(handle, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING) == nil
+}
@@ -0,0 +1,17 @@
+// +build windows
+
+package log
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"testing"
+)
+
+func TestEnableVirtualTerminalProcessingNotConsole(t *testing.T) {
+	f, err := ioutil.TempFile(t.TempDir(), "out")
+	assert.NoError(t, err)
+	defer f.Close()
+	assert.True(t, enableVirtualTerminalProcessing(f))
+	assert.NotPanics(t, enableVirtualTerminal)
+}
@@ -0,0 +1,94 @@
+package log
+
+import (
+	"container/list"
+	"context"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+// maxCooldownMessages bounds the number of recent messages remembered for the cooldown
+const maxCooldownMessages = 1024
+
+var cooldown = &messageCooldown{
+	recent: list.New(),
+	index:  map[cooldownKey]*list.Element{},
+}
+
+type cooldownKey struct {
+	level   logrus.Level
+	message string
+}
+
+type cooldownEntry struct {
+	key     cooldownKey
+	emitted time.Time
+}
+
+// messageCooldown is an LRU of recently emitted messages with the time they were last emitted
+type messageCooldown struct {
+	mu       sync.Mutex
+	duration time.Duration
+	recent   *list.List
+	index    map[cooldownKey]*list.Element
+}
+
+// SetMessageCooldown suppresses any message repeated with the same level and text within d of its
+// last emission. The output, the sinks and the hooks added with AddHook all drop the same entries,
+// hooks added to logrus directly still get every entry. 0 disables the cooldown
+func SetMessageCooldown(d time.Duration) {
+	cooldown.mu.Lock()
+	defer cooldown.mu.Unlock()
+	cooldown.duration = d
+	cooldown.recent.Init()
+	cooldown.index = map[cooldownKey]*list.Element{}
+}
+
+// cooldownDecisionKey is the context key under which suppressed keeps its decision for an entry
+type cooldownDecisionKey struct{}
+
+// suppressed reports whether the entry is on cooldown. The decision is taken by the first hook or
+// formatter that sees the entry and kept in its context, so it is not taken again for the others
+func suppressed(entry *logrus.Entry) bool {
+	ctx := entry.Context
+	if ctx == nil {
+		ctx = context.Background()
+	} else if decided, ok := ctx.Value(cooldownDecisionKey{}).(bool); ok {
+		return decided
+	}
+	decided := onCooldown(entry)
+	entry.Context = context.WithValue(ctx, cooldownDecisionKey{}, decided)
+	return decided
+}
+
+// onCooldown reports whether the entry repeats a message emitted within the cooldown, otherwise
+// it is recorded as emitted now
+func onCooldown(entry *logrus.Entry) bool {
+	c := cooldown
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.duration <= 0 {
+		return false
+	}
+
+	key := cooldownKey{level: entry.Level, message: entry.Message}
+	t := now()
+	if e, ok := c.index[key]; ok {
+		recent := e.Value.(*cooldownEntry)
+		if t.Sub(recent.emitted) < c.duration {
+			return true
+		}
+		recent.emitted = t
+		c.recent.MoveToFront(e)
+		return false
+	}
+
+	c.index[key] = c.recent.PushFront(&cooldownEntry{key: key, emitted: t})
+	if c.recent.Len() > maxCooldownMessages {
+		oldest := c.recent.Back()
+		c.recent.Remove(oldest)
+		delete(c.index, oldest.Value.(*cooldownEntry).key)
+	}
+	return false
+}
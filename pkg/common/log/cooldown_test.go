@@ -0,0 +1,68 @@
+package log
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSetMessageCooldown(t *testing.T) {
+	setFormatter("text")
+	clock := useFakeClock(t)
+	SetMessageCooldown(time.Minute)
+	t.Cleanup(func() { SetMessageCooldown(0) })
+
+	out := CaptureOutput(func() {
+		Logger().Warn("mirror is slow")
+		clock.advance(30 * time.Second)
+		Logger().Warn("mirror is slow")
+		Logger().Error("mirror is slow")
+		Logger().Warn("another message")
+		clock.advance(31 * time.Second)
+		Logger().Warn("mirror is slow")
+	})
+	assert.Equal(t, "WARNING: mirror is slow\nERROR: mirror is slow\nWARNING: another message\nWARNING: mirror is slow\n", out)
+}
+
+func TestMessageCooldownBounded(t *testing.T) {
+	setFormatter("text")
+	useFakeClock(t)
+	SetMessageCooldown(time.Minute)
+	t.Cleanup(func() { SetMessageCooldown(0) })
+
+	_ = CaptureOutput(func() {
+		for i := 0; i <= maxCooldownMessages; i++ {
+			Logger().Infof("message %d", i)
+		}
+	})
+	assert.Equal(t, maxCooldownMessages, cooldown.recent.Len())
+	assert.Len(t, cooldown.index, maxCooldownMessages)
+
+	// the oldest message was evicted so it is no longer suppressed
+	out := CaptureOutput(func() { Logger().Info("message 0") })
+	assert.Equal(t, "INFO: message 0\n", out)
+}
+
+func TestMessageCooldownHooksAndSinks(t *testing.T) {
+	restoreHooks(t)
+	setFormatter("text")
+	clock := useFakeClock(t)
+	SetMessageCooldown(time.Minute)
+	t.Cleanup(func() { SetMessageCooldown(0) })
+
+	audit := &bytes.Buffer{}
+	AddSink("audit", audit, "text")
+	hook := &captureHook{}
+	AddHook("webhook", hook)
+	out := CaptureOutput(func() {
+		Logger().Warn("mirror is slow")
+		clock.advance(30 * time.Second)
+		Logger().Warn("mirror is slow")
+		clock.advance(31 * time.Second)
+		Logger().Warn("mirror is slow")
+	})
+	assert.Equal(t, "WARNING: mirror is slow\nWARNING: mirror is slow\n", out)
+	assert.Equal(t, out, audit.String())
+	assert.Len(t, hook.entries, 2)
+}
@@ -0,0 +1,15 @@
+package log
+
+// LogCount logs n followed by the singular or plural wording at info level, e.g. "1 file installed"
+// or "3 files installed", with n as the count field
+func LogCount(n int, singular, plural string) {
+	Logger().WithField("count", n).Infof("%d %s", n, pluralize(n, singular, plural))
+}
+
+// pluralize returns singular when n is 1 and plural otherwise
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
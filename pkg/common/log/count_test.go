@@ -0,0 +1,29 @@
+package log
+
+import (
+	"github.com/Benbentwo/Windows10BootStrapper/pkg/common/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLogCount(t *testing.T) {
+	setFormatter("text")
+	tests := []struct {
+		name string
+		n    int
+		want string
+	}{
+		{"none", 0, "INFO: 0 files installed\n"},
+		{"one", 1, "INFO: 1 file installed\n"},
+		{"several", 2, "INFO: 2 files installed\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := CaptureOutput(func() { LogCount(tt.n, "file installed", "files installed") })
+			assert.Equal(t, tt.want, out)
+		})
+	}
+
+	entries := CaptureEntries(func() { LogCount(3, "file installed", "files installed") })
+	logtest.AssertFieldValue(t, entries, "count", 3)
+}
@@ -0,0 +1,39 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// deprecatedEnvVars maps renamed environment variables to their current name, add the old name here
+// when renaming a variable so users still setting it keep the behavior and get told to switch
+var deprecatedEnvVars = map[string]string{
+	"LOG_LAYOUT": "LOG_FORMAT",
+}
+
+// applyDeprecatedEnvVars copies every set deprecated variable to its current name unless that is set
+// too, returning the warnings to log once the logger is configured
+func applyDeprecatedEnvVars() []string {
+	deprecated := make([]string, 0, len(deprecatedEnvVars))
+	for name := range deprecatedEnvVars {
+		deprecated = append(deprecated, name)
+	}
+	sort.Strings(deprecated)
+
+	var warnings []string
+	for _, name := range deprecated {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		current := deprecatedEnvVars[name]
+		if _, set := os.LookupEnv(current); set {
+			warnings = append(warnings, fmt.Sprintf("environment variable %s is deprecated and ignored as %s is set", name, current))
+			continue
+		}
+		_ = os.Setenv(current, value)
+		warnings = append(warnings, fmt.Sprintf("environment variable %s is deprecated, use %s instead", name, current))
+	}
+	return warnings
+}
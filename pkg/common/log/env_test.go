@@ -0,0 +1,39 @@
+package log
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestDeprecatedEnvVars(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.Unsetenv("LOG_LAYOUT")
+		_ = os.Unsetenv("LOG_FORMAT")
+		logger = nil
+		_ = initializeLogger()
+	})
+
+	_ = os.Unsetenv("LOG_FORMAT")
+	_ = os.Setenv("LOG_LAYOUT", "json")
+	logger = nil
+	out := CaptureOutput(func() { _ = initializeLogger() })
+
+	assert.Contains(t, out, `"level":"warning"`)
+	assert.Contains(t, out, "environment variable LOG_LAYOUT is deprecated, use LOG_FORMAT instead")
+	assert.Equal(t, "json", os.Getenv("LOG_FORMAT"))
+	assert.IsType(t, &logrus.JSONFormatter{}, logrus.StandardLogger().Formatter.(*pipelineFormatter).Formatter)
+}
+
+func TestDeprecatedEnvVarsCurrentSet(t *testing.T) {
+	t.Cleanup(func() {
+		_ = os.Unsetenv("LOG_LAYOUT")
+		_ = os.Unsetenv("LOG_FORMAT")
+	})
+
+	_ = os.Setenv("LOG_LAYOUT", "json")
+	_ = os.Setenv("LOG_FORMAT", "text")
+	assert.Equal(t, []string{"environment variable LOG_LAYOUT is deprecated and ignored as LOG_FORMAT is set"}, applyDeprecatedEnvVars())
+	assert.Equal(t, "text", os.Getenv("LOG_FORMAT"))
+}
@@ -0,0 +1,116 @@
+package log
+
+import (
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"sync"
+)
+
+// maxErrorContextEntries bounds the entries kept as error context, the oldest are dropped first
+const maxErrorContextEntries = 100
+
+var errContext = &errorContext{}
+
+// errorContext keeps the latest entries more verbose than the display level to show them before an error
+type errorContext struct {
+	mu      sync.Mutex
+	enabled bool
+	level   logrus.Level
+	// displayLevel is the level set with SetLevel, entries above it are only shown as error context
+	displayLevel logrus.Level
+	buffered     []*logrus.Entry
+}
+
+// SetErrorContextLevel keeps the latest entries more verbose than the configured level, up to the given
+// one, and shows them just before the next error as its context, e.g. "debug" to see what led to an
+// error without debug output otherwise. The logrus level is raised to the given one to receive these
+// entries, IsLevelEnabled, the sinks, the hooks added with AddHook and CaptureEntries still only see
+// the entries shown, hooks added to logrus directly see them all. An empty level turns it off
+func SetErrorContextLevel(s string) error {
+	c := errContext
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s == "" {
+		if c.enabled {
+			logrus.SetLevel(c.displayLevel)
+		}
+		c.enabled = false
+		c.buffered = nil
+		return nil
+	}
+
+	level, err := logrus.ParseLevel(s)
+	if err != nil {
+		return errors.Errorf("Invalid log level '%s'", s)
+	}
+	if !c.enabled {
+		c.displayLevel = logrus.GetLevel()
+	}
+	c.enabled = true
+	c.level = level
+	logrus.SetLevel(c.loggerLevel())
+	return nil
+}
+
+// setDisplayLevel sets the level entries are shown at, the standard logger stays at the error context
+// level while that is more verbose
+func setDisplayLevel(level logrus.Level) {
+	c := errContext
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.enabled {
+		logrus.SetLevel(level)
+		return
+	}
+	c.displayLevel = level
+	logrus.SetLevel(c.loggerLevel())
+}
+
+// loggerLevel returns the more verbose of the display and error context levels
+func (c *errorContext) loggerLevel() logrus.Level {
+	if c.level > c.displayLevel {
+		return c.level
+	}
+	return c.displayLevel
+}
+
+// IsLevelEnabled reports whether entries at the level are shown, unlike logrus.IsLevelEnabled it is
+// false for the levels only kept as error context
+func IsLevelEnabled(level logrus.Level) bool {
+	return displayed(level)
+}
+
+// displayed reports whether entries at the level are shown, rather than only kept as error context
+func displayed(level logrus.Level) bool {
+	c := errContext
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.enabled {
+		return level <= c.displayLevel
+	}
+	return logrus.IsLevelEnabled(level)
+}
+
+// withErrorContext reports whether the entry is held back as error context, otherwise it returns the
+// entries to show before it, which are only ever returned for an error
+func withErrorContext(entry *logrus.Entry) (context []*logrus.Entry, held bool) {
+	c := errContext
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.enabled {
+		return nil, false
+	}
+	if entry.Level > c.displayLevel {
+		if len(c.buffered) == maxErrorContextEntries {
+			c.buffered = c.buffered[1:]
+		}
+		c.buffered = append(c.buffered, copyEntry(entry))
+		return nil, true
+	}
+	if entry.Level > logrus.ErrorLevel {
+		return nil, false
+	}
+	context = c.buffered
+	c.buffered = nil
+	return context, false
+}
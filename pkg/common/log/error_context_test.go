@@ -0,0 +1,69 @@
+package log
+
+import (
+	"bytes"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetErrorContextLevel(t *testing.T) {
+	restoreHooks(t)
+	t.Cleanup(func() {
+		_ = SetErrorContextLevel("")
+		_ = SetLevel("info")
+	})
+	setFormatter("text")
+	_ = SetLevel("info")
+	audit := &bytes.Buffer{}
+	AddSink("audit", audit, "text")
+
+	assert.NoError(t, SetErrorContextLevel("debug"))
+	out := CaptureOutput(func() {
+		Logger().Debug("resolving mirror")
+		Logger().Info("downloading")
+		Logger().Debug("mirror timed out")
+		Logger().Error("download failed")
+		Logger().Debug("cleaning up")
+		Logger().Info("done")
+	})
+	assert.Equal(t, "INFO: downloading\nDEBUG: resolving mirror\nDEBUG: mirror timed out\nERROR: download failed\nINFO: done\n", out)
+	assert.Equal(t, "INFO: downloading\nERROR: download failed\nINFO: done\n", audit.String())
+
+	assert.False(t, IsLevelEnabled(logrus.DebugLevel))
+	assert.False(t, HCLogAdapter().IsDebug())
+	assert.True(t, IsLevelEnabled(logrus.InfoLevel))
+
+	assert.NoError(t, SetLevel("warn"))
+	assert.Equal(t, logrus.DebugLevel, logrus.GetLevel())
+	out = CaptureOutput(func() {
+		Logger().Info("hidden context")
+		Logger().Warn("shown")
+	})
+	assert.Equal(t, "WARNING: shown\n", out)
+
+	assert.NoError(t, SetErrorContextLevel(""))
+	assert.Equal(t, logrus.WarnLevel, logrus.GetLevel())
+	out = CaptureOutput(func() { Logger().Error("no context") })
+	assert.Equal(t, "ERROR: no context\n", out)
+
+	assert.EqualError(t, SetErrorContextLevel("loud"), "Invalid log level 'loud'")
+}
+
+func TestErrorContextBounded(t *testing.T) {
+	restoreHooks(t)
+	t.Cleanup(func() { _ = SetErrorContextLevel("") })
+	setFormatter("text")
+	ensureLevelCounter()
+	counter.reset()
+	assert.NoError(t, SetErrorContextLevel("trace"))
+
+	entries := CaptureEntries(func() {
+		for i := 0; i < maxErrorContextEntries+10; i++ {
+			Logger().Trace("step")
+		}
+	})
+	assert.Empty(t, entries)
+	assert.Equal(t, uint64(0), LevelCounts()["trace"])
+	assert.Len(t, errContext.buffered, maxErrorContextEntries)
+}
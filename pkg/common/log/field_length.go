@@ -0,0 +1,64 @@
+package log
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// maxFieldValueLength is the maximum number of characters of a string field value, 0 is unlimited
+var maxFieldValueLength int32
+
+// SetMaxFieldValueLength truncates string field values longer than n characters to n followed by
+// "…(N chars)" with the original length, in every format. 0 means unlimited
+func SetMaxFieldValueLength(n int) {
+	if n < 0 {
+		n = 0
+	}
+	atomic.StoreInt32(&maxFieldValueLength, int32(n))
+}
+
+// truncateFieldValues returns the entry with long string field values truncated, the data of the
+// given entry is left untouched as it may be shared with other entries
+func truncateFieldValues(entry *logrus.Entry) *logrus.Entry {
+	limit := int(atomic.LoadInt32(&maxFieldValueLength))
+	if limit == 0 {
+		return entry
+	}
+	var data logrus.Fields
+	for k, v := range entry.Data {
+		s, ok := v.(string)
+		if !ok || len(s) <= limit {
+			continue
+		}
+		length := utf8.RuneCountInString(s)
+		if length <= limit {
+			continue
+		}
+		if data == nil {
+			data = make(logrus.Fields, len(entry.Data))
+			for k, v := range entry.Data {
+				data[k] = v
+			}
+		}
+		data[k] = fmt.Sprintf("%s…(%d chars)", truncateRunes(s, limit), length)
+	}
+	if data == nil {
+		return entry
+	}
+	truncated := *entry
+	truncated.Data = data
+	return &truncated
+}
+
+// truncateRunes returns the first n runes of s
+func truncateRunes(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
@@ -0,0 +1,47 @@
+package log
+
+import (
+	"encoding/json"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestSetMaxFieldValueLength(t *testing.T) {
+	SetMaxFieldValueLength(10)
+	t.Cleanup(func() {
+		SetMaxFieldValueLength(0)
+		setFormatter("text")
+	})
+	content := strings.Repeat("a", 5000)
+	fields := logrus.Fields{"content": content, "name": "short", "size": 5000}
+
+	setFormatter("json")
+	out := CaptureOutput(func() { Logger().WithFields(fields).Info("wrote file") })
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(out), &got))
+	assert.Equal(t, "aaaaaaaaaa…(5000 chars)", got["content"])
+	assert.Equal(t, "short", got["name"])
+	assert.Equal(t, float64(5000), got["size"])
+
+	logrus.SetFormatter(&pipelineFormatter{Formatter: &CustomTextFormat{ShowFields: true}})
+	out = CaptureOutput(func() { Logger().WithFields(fields).Info("wrote file") })
+	assert.Equal(t, `INFO: wrote file content="aaaaaaaaaa…(5000 chars)" name=short size=5000`+"\n", out)
+	assert.Equal(t, content, fields["content"])
+}
+
+func TestTruncateFieldValuesRunes(t *testing.T) {
+	SetMaxFieldValueLength(3)
+	t.Cleanup(func() { SetMaxFieldValueLength(0) })
+
+	entry := &logrus.Entry{Data: logrus.Fields{"path": "C:\\Users\\ünïcödé", "ok": "äöü"}}
+	truncated := truncateFieldValues(entry)
+	assert.Equal(t, "C:\\…(16 chars)", truncated.Data["path"])
+	assert.Equal(t, "äöü", truncated.Data["ok"])
+	assert.Equal(t, "C:\\Users\\ünïcödé", entry.Data["path"])
+
+	untouched := &logrus.Entry{Data: logrus.Fields{"ok": "abc"}}
+	assert.Same(t, untouched, truncateFieldValues(untouched))
+}
@@ -0,0 +1,20 @@
+package log
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+// contextFields returns the package level fields added to every entry obtained from Logger()
+func contextFields() logrus.Fields {
+	fields := logrus.Fields{}
+	for _, provided := range []logrus.Fields{
+		scopeFields(),
+		subCommandFields(),
+		operationFields(),
+	} {
+		for k, v := range provided {
+			fields[k] = v
+		}
+	}
+	return fields
+}
@@ -0,0 +1,33 @@
+package log
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+// fileOpPastTense maps the common file operations to the verb logged on success
+var fileOpPastTense = map[string]string{
+	"create": "created",
+	"copy":   "copied",
+	"delete": "deleted",
+	"chmod":  "changed permissions of",
+	"move":   "moved",
+	"write":  "wrote",
+}
+
+// LogFileOp logs the outcome of a file operation such as create, copy, delete or chmod, e.g.
+// "created /path/to/x" at info level, or at error level with the op, path and error as fields
+func LogFileOp(op string, path string, err error) {
+	entry := Logger().WithFields(logrus.Fields{
+		"op":   op,
+		"path": path,
+	})
+	if err != nil {
+		entry.WithError(err).Errorf("failed to %s %s: %v", op, path, err)
+		return
+	}
+	verb, ok := fileOpPastTense[op]
+	if !ok {
+		verb = op
+	}
+	entry.Infof("%s %s", verb, path)
+}
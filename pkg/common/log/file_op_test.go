@@ -0,0 +1,40 @@
+package log
+
+import (
+	"errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLogFileOp(t *testing.T) {
+	setFormatter("text")
+	tests := []struct {
+		name string
+		op   string
+		path string
+		err  error
+		want string
+	}{
+		{"create", "create", "/path/to/x", nil, "INFO: created /path/to/x\n"},
+		{"copy", "copy", "/path/to/x", nil, "INFO: copied /path/to/x\n"},
+		{"delete", "delete", "/path/to/x", nil, "INFO: deleted /path/to/x\n"},
+		{"chmod", "chmod", "/path/to/x", nil, "INFO: changed permissions of /path/to/x\n"},
+		{"unknown", "touch", "/path/to/x", nil, "INFO: touch /path/to/x\n"},
+		{"failure", "copy", "/path/to/x", errors.New("permission denied"), "ERROR: failed to copy /path/to/x: permission denied\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := CaptureOutput(func() { LogFileOp(tt.op, tt.path, tt.err) })
+			assert.Equal(t, tt.want, out)
+		})
+	}
+}
+
+func TestLogFileOpFields(t *testing.T) {
+	err := errors.New("permission denied")
+	entries := CaptureEntries(func() { LogFileOp("delete", "/tmp/x", err) })
+	assert.Len(t, entries, 1)
+	assert.Equal(t, logrus.ErrorLevel, entries[0].Level)
+	assert.Equal(t, logrus.Fields{"op": "delete", "path": "/tmp/x", logrus.ErrorKey: err}, entries[0].Data)
+}
@@ -0,0 +1,128 @@
+package log
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-hclog"
+	"github.com/sirupsen/logrus"
+	"io"
+	stdlog "log"
+)
+
+// hclogAdapter is a hclog.Logger writing through the default logger
+type hclogAdapter struct {
+	name string
+	args []interface{}
+}
+
+// HCLogAdapter returns a hclog.Logger for dependencies expecting one, everything it logs goes through
+// the formatter and level of the default logger with the key/value pairs as fields. The level is
+// configured here only, SetLevel on the adapter does nothing
+func HCLogAdapter() hclog.Logger {
+	return &hclogAdapter{}
+}
+
+// Log logs the message at the hclog level, nothing is logged for hclog.Off
+func (a *hclogAdapter) Log(level hclog.Level, msg string, args ...interface{}) {
+	if level == hclog.Off {
+		return
+	}
+	if a.name != "" {
+		msg = a.name + ": " + msg
+	}
+	a.entry(args).Log(logrusLevel(level), msg)
+}
+
+func (a *hclogAdapter) Trace(msg string, args ...interface{}) { a.Log(hclog.Trace, msg, args...) }
+func (a *hclogAdapter) Debug(msg string, args ...interface{}) { a.Log(hclog.Debug, msg, args...) }
+func (a *hclogAdapter) Info(msg string, args ...interface{})  { a.Log(hclog.Info, msg, args...) }
+func (a *hclogAdapter) Warn(msg string, args ...interface{})  { a.Log(hclog.Warn, msg, args...) }
+func (a *hclogAdapter) Error(msg string, args ...interface{}) { a.Log(hclog.Error, msg, args...) }
+
+func (a *hclogAdapter) IsTrace() bool { return IsLevelEnabled(logrus.TraceLevel) }
+func (a *hclogAdapter) IsDebug() bool { return IsLevelEnabled(logrus.DebugLevel) }
+func (a *hclogAdapter) IsInfo() bool  { return IsLevelEnabled(logrus.InfoLevel) }
+func (a *hclogAdapter) IsWarn() bool  { return IsLevelEnabled(logrus.WarnLevel) }
+func (a *hclogAdapter) IsError() bool { return IsLevelEnabled(logrus.ErrorLevel) }
+
+// ImpliedArgs returns the key/value pairs added with With
+func (a *hclogAdapter) ImpliedArgs() []interface{} {
+	return a.args
+}
+
+// With returns an adapter adding the key/value pairs to everything it logs
+func (a *hclogAdapter) With(args ...interface{}) hclog.Logger {
+	implied := make([]interface{}, 0, len(a.args)+len(args))
+	implied = append(append(implied, a.args...), args...)
+	return &hclogAdapter{name: a.name, args: implied}
+}
+
+// Name returns the name prefixed to every message
+func (a *hclogAdapter) Name() string {
+	return a.name
+}
+
+// Named returns an adapter with the name appended to the current one
+func (a *hclogAdapter) Named(name string) hclog.Logger {
+	if a.name != "" {
+		name = a.name + "." + name
+	}
+	return a.ResetNamed(name)
+}
+
+// ResetNamed returns an adapter with the name replacing the current one
+func (a *hclogAdapter) ResetNamed(name string) hclog.Logger {
+	return &hclogAdapter{name: name, args: a.args}
+}
+
+// SetLevel does nothing, the level of the default logger applies
+func (a *hclogAdapter) SetLevel(hclog.Level) {}
+
+// StandardLogger returns a standard library logger writing through the adapter
+func (a *hclogAdapter) StandardLogger(opts *hclog.StandardLoggerOptions) *stdlog.Logger {
+	return stdlog.New(a.StandardWriter(opts), "", 0)
+}
+
+// StandardWriter returns a writer logging the lines as hclog does: with ForceLevel every line at that
+// level with any "[LEVEL]" prefix removed, with InferLevels at the level named by the prefix or info
+// without one and otherwise every line as is at info
+func (a *hclogAdapter) StandardWriter(opts *hclog.StandardLoggerOptions) io.Writer {
+	w := NewParsingWriter()
+	switch {
+	case opts != nil && opts.ForceLevel != hclog.NoLevel:
+		w.defaultLevel = logrusLevel(opts.ForceLevel)
+		w.forceLevel = true
+	case opts == nil || !opts.InferLevels:
+		w.tokens = nil
+	}
+	return w
+}
+
+// entry returns an entry with the implied and given key/value pairs as fields, a value without a key
+// is added as hclog does under hclog.MissingKey
+func (a *hclogAdapter) entry(args []interface{}) *logrus.Entry {
+	pairs := append(append([]interface{}{}, a.args...), args...)
+	if len(pairs)%2 != 0 {
+		pairs = append(pairs[:len(pairs)-1], hclog.MissingKey, pairs[len(pairs)-1])
+	}
+	fields := make(logrus.Fields, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		fields[fmt.Sprint(pairs[i])] = pairs[i+1]
+	}
+	return Logger().WithFields(fields)
+}
+
+// logrusLevel maps a hclog level to the logrus level, hclog.NoLevel logs at info
+func logrusLevel(level hclog.Level) logrus.Level {
+	switch level {
+	case hclog.Trace:
+		return logrus.TraceLevel
+	case hclog.Debug:
+		return logrus.DebugLevel
+	case hclog.Warn:
+		return logrus.WarnLevel
+	case hclog.Error:
+		return logrus.ErrorLevel
+	default:
+		return logrus.InfoLevel
+	}
+}
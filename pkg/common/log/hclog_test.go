@@ -0,0 +1,77 @@
+package log
+
+import (
+	"github.com/hashicorp/go-hclog"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestHCLogAdapter(t *testing.T) {
+	logrus.SetFormatter(&CustomTextFormat{ShowFields: true})
+	t.Cleanup(func() {
+		setFormatter("text")
+		_ = SetLevel("info")
+	})
+
+	adapter := HCLogAdapter()
+	out := CaptureOutput(func() {
+		adapter.Info("plugin started", "pid", 42)
+		adapter.Named("raft").With("node", "a").Warn("election timeout", "term", 3, "dangling")
+		adapter.Debug("hidden")
+		adapter.Log(hclog.Off, "hidden")
+	})
+	assert.Equal(t, "INFO: plugin started pid=42\n"+
+		"WARNING: raft: election timeout EXTRA_VALUE_AT_END=dangling node=a term=3\n", out)
+
+	_ = SetLevel("debug")
+	assert.True(t, adapter.IsDebug())
+	assert.False(t, adapter.IsTrace())
+	adapter.SetLevel(hclog.Error)
+	assert.Equal(t, logrus.DebugLevel, logrus.GetLevel())
+}
+
+func TestHCLogAdapterStandardLogger(t *testing.T) {
+	setFormatter("text")
+	adapter := HCLogAdapter()
+
+	tests := []struct {
+		name string
+		opts *hclog.StandardLoggerOptions
+		want string
+	}{
+		{"force level", &hclog.StandardLoggerOptions{ForceLevel: hclog.Warn}, "WARNING: connection lost\nWARNING: retrying\n"},
+		{"infer levels", &hclog.StandardLoggerOptions{InferLevels: true}, "ERROR: connection lost\nINFO: retrying\n"},
+		{"force level overrides infer levels", &hclog.StandardLoggerOptions{InferLevels: true, ForceLevel: hclog.Warn}, "WARNING: connection lost\nWARNING: retrying\n"},
+		{"as is", nil, "INFO: [ERROR] connection lost\nINFO: retrying\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := CaptureOutput(func() {
+				std := adapter.StandardLogger(tt.opts)
+				std.Print("[ERROR] connection lost")
+				std.Print("retrying")
+			})
+			assert.Equal(t, tt.want, out)
+		})
+	}
+}
+
+func Test_logrusLevel(t *testing.T) {
+	tests := []struct {
+		level hclog.Level
+		want  logrus.Level
+	}{
+		{hclog.NoLevel, logrus.InfoLevel},
+		{hclog.Trace, logrus.TraceLevel},
+		{hclog.Debug, logrus.DebugLevel},
+		{hclog.Info, logrus.InfoLevel},
+		{hclog.Warn, logrus.WarnLevel},
+		{hclog.Error, logrus.ErrorLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			assert.Equal(t, tt.want, logrusLevel(tt.level))
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package log
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"io"
+	"os"
+	"reflect"
+	"sync"
+)
+
+// namedHook is a hook registered with AddHook, keeping the name reported by ActiveHooks
+type namedHook struct {
+	name string
+	logrus.Hook
+}
+
+// sinkHook writes every entry formatted with its own formatter to an additional writer
+type sinkHook struct {
+	name      string
+	out       io.Writer
+	formatter logrus.Formatter
+	mu        *sync.Mutex
+}
+
+var (
+	sinkLocksMu sync.Mutex
+	sinkLocks   = map[io.Writer]*sharedLock{}
+)
+
+// sharedLock is the lock of a writer with the number of sinks writing to it
+type sharedLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// AddHook registers the hook on the default logger under a descriptive name reported by ActiveHooks
+func AddHook(name string, hook logrus.Hook) {
+	logrus.AddHook(&namedHook{name: name, Hook: hook})
+}
+
+// AddSink writes every entry to w formatted with the given layout, in addition to the output of the
+// default logger. The name is reported by ActiveSinks and used by RemoveSink
+func AddSink(name string, w io.Writer, layout FormatLayoutType) {
+	logrus.AddHook(newSinkHook(name, w, layout))
+}
+
+// newSinkHook returns the hook writing entries to w formatted with the given layout
+func newSinkHook(name string, w io.Writer, layout FormatLayoutType) *sinkHook {
+	return &sinkHook{name: name, out: w, formatter: newFormatter(layout), mu: sinkLock(w)}
+}
+
+// sinkLock returns the lock serializing the writes of every sink writing to w, so entries formatted
+// differently for the same writer never interleave. Each call must be matched by releaseSinkLock
+func sinkLock(w io.Writer) *sync.Mutex {
+	if !reflect.TypeOf(w).Comparable() {
+		return &sync.Mutex{}
+	}
+	sinkLocksMu.Lock()
+	defer sinkLocksMu.Unlock()
+	lock, ok := sinkLocks[w]
+	if !ok {
+		lock = &sharedLock{}
+		sinkLocks[w] = lock
+	}
+	lock.refs++
+	return &lock.mu
+}
+
+// releaseSinkLock forgets the lock of w once no sink writes to it anymore
+func releaseSinkLock(w io.Writer) {
+	if !reflect.TypeOf(w).Comparable() {
+		return
+	}
+	sinkLocksMu.Lock()
+	defer sinkLocksMu.Unlock()
+	if lock, ok := sinkLocks[w]; ok {
+		if lock.refs--; lock.refs <= 0 {
+			delete(sinkLocks, w)
+		}
+	}
+}
+
+// RemoveSink removes every sink added with the given name
+func RemoveSink(name string) {
+	removeSinks(func(sink *sinkHook) bool {
+		return sink.name == name
+	})
+}
+
+// removeSinks unregisters every sink matching from the default logger and releases the locks of their
+// writers
+func removeSinks(match func(*sinkHook) bool) {
+	removed := map[*sinkHook]bool{}
+	for _, hook := range registeredHooks() {
+		if sink, ok := hook.(*sinkHook); ok && match(sink) {
+			removed[sink] = true
+		}
+	}
+	removeHooks(func(hook logrus.Hook) bool {
+		sink, ok := hook.(*sinkHook)
+		return ok && removed[sink]
+	})
+	for sink := range removed {
+		releaseSinkLock(sink.out)
+	}
+}
+
+// ActiveHooks returns the names of the hooks registered on the default logger in registration order,
+// a hook registered twice is listed twice. Hooks not added with AddHook are named by their type
+func ActiveHooks() []string {
+	var names []string
+	for _, hook := range registeredHooks() {
+		switch h := hook.(type) {
+		case *sinkHook, *captureHook:
+		case *namedHook:
+			names = append(names, h.name)
+		case *levelCounter:
+			names = append(names, "level-counter")
+		default:
+			names = append(names, fmt.Sprintf("%T", hook))
+		}
+	}
+	return names
+}
+
+// ActiveSinks returns the name of the output of the default logger followed by the names of the sinks
+func ActiveSinks() []string {
+	names := []string{outputName(currentOutput())}
+	for _, hook := range registeredHooks() {
+		if sink, ok := hook.(*sinkHook); ok {
+			names = append(names, sink.name)
+		}
+	}
+	return names
+}
+
+// Fire delivers the entry, prepared like for the formatters, to the hook unless hooks are paused, the
+// package was shut down, the entry is on cooldown or only kept as error context
+func (h *namedHook) Fire(entry *logrus.Entry) error {
+	if isShutDown() || !displayed(entry.Level) || suppressed(entry) {
+		return nil
+	}
+	entry = prepareEntry(entry)
+	if holdDelivery(h.Hook, entry) {
+		return nil
+	}
+	return h.Hook.Fire(entry)
+}
+
+// Levels returns the levels the sink writes
+func (s *sinkHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire writes the formatted entry to the sink in a single write, nothing is written for entries on
+// cooldown, only kept as error context or once the package was shut down as the sink may be closed
+func (s *sinkHook) Fire(entry *logrus.Entry) error {
+	if isShutDown() || !displayed(entry.Level) || suppressed(entry) {
+		return nil
+	}
+	serialized, err := s.formatter.Format(prepareEntry(entry))
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.out.Write(terminateRecord(s.formatter, serialized))
+	return err
+}
+
+// outputName describes the output writer
+func outputName(out io.Writer) string {
+	switch w := out.(type) {
+	case *networkWriter:
+		return w.network + "://" + w.addr
+	case *os.File:
+		switch w {
+		case os.Stderr:
+			return "stderr"
+		case os.Stdout:
+			return "stdout"
+		}
+		return w.Name()
+	default:
+		return fmt.Sprintf("%T", out)
+	}
+}
+
+// registeredHooks returns every hook registered on the default logger once, in registration order
+func registeredHooks() []logrus.Hook {
+	var hooks []logrus.Hook
+	seen := map[logrus.Hook]bool{}
+	for _, level := range logrus.AllLevels {
+		for _, hook := range logrus.StandardLogger().Hooks[level] {
+			comparable := reflect.TypeOf(hook).Comparable()
+			if comparable && seen[hook] {
+				continue
+			}
+			if comparable {
+				seen[hook] = true
+			}
+			hooks = append(hooks, hook)
+		}
+	}
+	return hooks
+}
+
+// hasHook reports whether the hook is registered on the default logger, directly or with AddHook
+func hasHook(hook logrus.Hook) bool {
+	for _, registered := range registeredHooks() {
+		if named, ok := registered.(*namedHook); ok {
+			registered = named.Hook
+		}
+		if registered == hook {
+			return true
+		}
+	}
+	return false
+}
+
+// removeHook unregisters the hook from the default logger, leaving all other hooks in place
+func removeHook(hook logrus.Hook) {
+	removeHooks(func(h logrus.Hook) bool {
+		return h == hook
+	})
+}
+
+// removeHooks unregisters every hook matching from the default logger
+func removeHooks(match func(logrus.Hook) bool) {
+	hooks := make(logrus.LevelHooks)
+	for level, levelHooks := range logrus.StandardLogger().Hooks {
+		for _, h := range levelHooks {
+			if !match(h) {
+				hooks[level] = append(hooks[level], h)
+			}
+		}
+	}
+	logrus.StandardLogger().ReplaceHooks(hooks)
+}
@@ -0,0 +1,137 @@
+package log
+
+import (
+	"bytes"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type nopHook struct{}
+
+func (nopHook) Levels() []logrus.Level   { return logrus.AllLevels }
+func (nopHook) Fire(*logrus.Entry) error { return nil }
+
+func TestActiveHooks(t *testing.T) {
+	restoreHooks(t)
+	logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks))
+
+	AddHook("webhook", nopHook{})
+	AddHook("webhook", nopHook{})
+	logrus.AddHook(nopHook{})
+	ensureLevelCounter()
+	ensureLevelCounter()
+	AddSink("audit", &bytes.Buffer{}, "json")
+
+	assert.Equal(t, []string{"webhook", "webhook", "log.nopHook", "level-counter"}, ActiveHooks())
+}
+
+func TestActiveSinks(t *testing.T) {
+	restoreHooks(t)
+	t.Cleanup(func() { SetOutput(os.Stderr) })
+	setFormatter("text")
+
+	audit := &bytes.Buffer{}
+	trace := &bytes.Buffer{}
+	AddSink("audit", audit, "json")
+	AddSink("trace", trace, "text")
+	SetOutput(os.Stdout)
+	assert.Equal(t, []string{"stdout", "audit", "trace"}, ActiveSinks())
+
+	out := CaptureOutput(func() { Logger().Info("installed") })
+	assert.Equal(t, "INFO: installed\n", out)
+	assert.Contains(t, audit.String(), `"msg":"installed"`)
+	assert.Equal(t, "INFO: installed\n", trace.String())
+	assert.Equal(t, []string{"stderr", "audit", "trace"}, ActiveSinks())
+
+	RemoveSink("audit")
+	assert.Equal(t, []string{"stderr", "trace"}, ActiveSinks())
+}
+
+// byteWriter writes one byte at a time, yielding in between, so unserialized writes interleave
+type byteWriter struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (w *byteWriter) Write(p []byte) (int, error) {
+	for _, c := range p {
+		w.mu.Lock()
+		w.buf.WriteByte(c)
+		w.mu.Unlock()
+		runtime.Gosched()
+	}
+	return len(p), nil
+}
+
+func TestSinkConcurrentWrites(t *testing.T) {
+	restoreHooks(t)
+	logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks))
+	setFormatter("text")
+	out := &byteWriter{}
+	AddSink("text", out, "text")
+	AddSink("json", out, "json")
+
+	var wg sync.WaitGroup
+	_ = CaptureOutput(func() {
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 20; j++ {
+					Logger().Infof("worker %d message %d", i, j)
+				}
+			}(i)
+		}
+		wg.Wait()
+	})
+
+	lines := strings.Split(strings.TrimSuffix(out.buf.String(), "\n"), "\n")
+	assert.Len(t, lines, 2*8*20)
+	line := regexp.MustCompile(`^(INFO: worker \d message \d+|\{"level":"info","msg":"worker \d message \d+","time":"[^"]+"\})$`)
+	for _, l := range lines {
+		assert.Regexp(t, line, l)
+	}
+}
+
+func TestRemoveSinkReleasesLock(t *testing.T) {
+	restoreHooks(t)
+	out := &bytes.Buffer{}
+	AddSink("text", out, "text")
+	AddSink("json", out, "json")
+
+	RemoveSink("text")
+	sinkLocksMu.Lock()
+	assert.Equal(t, 1, sinkLocks[out].refs)
+	sinkLocksMu.Unlock()
+
+	RemoveSink("json")
+	sinkLocksMu.Lock()
+	_, ok := sinkLocks[out]
+	sinkLocksMu.Unlock()
+	assert.False(t, ok)
+}
+
+// sliceHook is a value hook that cannot be used as a map key
+type sliceHook struct {
+	levels []logrus.Level
+}
+
+func (h sliceHook) Levels() []logrus.Level   { return h.levels }
+func (sliceHook) Fire(*logrus.Entry) error { return nil }
+
+func TestRemoveSinkWithUncomparableHook(t *testing.T) {
+	restoreHooks(t)
+	logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks))
+	logrus.AddHook(sliceHook{levels: logrus.AllLevels})
+	AddSink("audit", &bytes.Buffer{}, "json")
+
+	assert.NotPanics(t, func() { RemoveSink("audit") })
+	assert.Equal(t, []string{"stderr"}, ActiveSinks())
+	assert.Len(t, logrus.StandardLogger().Hooks[logrus.InfoLevel], 1)
+}
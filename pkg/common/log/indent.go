@@ -0,0 +1,57 @@
+package log
+
+import (
+	"strings"
+	"sync"
+)
+
+// IndentStyle the way nested text output is indented
+type IndentStyle int
+
+const (
+	// Spaces indents each depth with two spaces
+	Spaces IndentStyle = iota
+	// Guides indents each depth with a vertical guide so the nesting stays visible
+	Guides
+)
+
+var (
+	indentMu    sync.RWMutex
+	indentDepth int
+	indentStyle = Spaces
+)
+
+// IncreaseIndent nests the following text output one level deeper e.g. for the steps of a phase
+func IncreaseIndent() {
+	indentMu.Lock()
+	defer indentMu.Unlock()
+	indentDepth++
+}
+
+// DecreaseIndent undoes the last IncreaseIndent
+func DecreaseIndent() {
+	indentMu.Lock()
+	defer indentMu.Unlock()
+	if indentDepth > 0 {
+		indentDepth--
+	}
+}
+
+// SetIndentStyle sets how the indent depth is rendered in text output
+func SetIndentStyle(style IndentStyle) {
+	indentMu.Lock()
+	defer indentMu.Unlock()
+	indentStyle = style
+}
+
+// indentPrefix returns the prefix rendered before the message for the current indent depth
+func indentPrefix() string {
+	indentMu.RLock()
+	defer indentMu.RUnlock()
+	switch indentStyle {
+	case Guides:
+		return strings.Repeat("│ ", indentDepth)
+	default:
+		return strings.Repeat("  ", indentDepth)
+	}
+}
@@ -0,0 +1,40 @@
+package log
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetIndentStyle(t *testing.T) {
+	setFormatter("text")
+	t.Cleanup(func() { SetIndentStyle(Spaces) })
+
+	tests := []struct {
+		name  string
+		style IndentStyle
+		want  string
+	}{
+		{"Spaces", Spaces, "INFO: phase\nINFO:   step\nINFO:     detail\n"},
+		{"Guides", Guides, "INFO: phase\nINFO: │ step\nINFO: │ │ detail\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetIndentStyle(tt.style)
+			out := CaptureOutput(func() {
+				Logger().Info("phase")
+				IncreaseIndent()
+				Logger().Info("step")
+				IncreaseIndent()
+				Logger().Info("detail")
+			})
+			DecreaseIndent()
+			DecreaseIndent()
+			assert.Equal(t, tt.want, out)
+		})
+	}
+}
+
+func TestDecreaseIndentAtZero(t *testing.T) {
+	DecreaseIndent()
+	assert.Equal(t, "", indentPrefix())
+}
@@ -0,0 +1,86 @@
+package log
+
+import (
+	"github.com/sirupsen/logrus"
+	"sync"
+)
+
+// Interface is the logger interface consumer code can accept instead of calling Logger() directly,
+// so tests can inject a fake with SetDefault. It is not named Logger as Logger() is the package accessor
+type Interface interface {
+	Tracef(format string, args ...interface{})
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+	WithField(key string, value interface{}) Interface
+	WithFields(fields logrus.Fields) Interface
+	WithError(err error) Interface
+}
+
+var (
+	defaultMu sync.RWMutex
+	defaultOf Interface
+)
+
+// Default returns the logger set with SetDefault, or one backed by Logger() when none was set
+func Default() Interface {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	if defaultOf != nil {
+		return defaultOf
+	}
+	return entryLogger{}
+}
+
+// SetDefault replaces the logger returned by Default, nil restores the one backed by Logger()
+func SetDefault(l Interface) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultOf = l
+}
+
+// entryLogger implements Interface on top of a logrus entry, the zero value uses Logger() on every
+// call so the package level fields are up to date
+type entryLogger struct {
+	entry *logrus.Entry
+}
+
+func (l entryLogger) resolve() *logrus.Entry {
+	if l.entry != nil {
+		return l.entry
+	}
+	return Logger()
+}
+
+func (l entryLogger) Tracef(format string, args ...interface{}) {
+	l.resolve().Tracef(format, args...)
+}
+
+func (l entryLogger) Debugf(format string, args ...interface{}) {
+	l.resolve().Debugf(format, args...)
+}
+
+func (l entryLogger) Infof(format string, args ...interface{}) {
+	l.resolve().Infof(format, args...)
+}
+
+func (l entryLogger) Warnf(format string, args ...interface{}) {
+	l.resolve().Warnf(format, args...)
+}
+
+func (l entryLogger) Errorf(format string, args ...interface{}) {
+	l.resolve().Errorf(format, args...)
+}
+
+func (l entryLogger) WithField(key string, value interface{}) Interface {
+	return entryLogger{l.resolve().WithField(key, value)}
+}
+
+func (l entryLogger) WithFields(fields logrus.Fields) Interface {
+	return entryLogger{l.resolve().WithFields(fields)}
+}
+
+func (l entryLogger) WithError(err error) Interface {
+	return entryLogger{l.resolve().WithError(err)}
+}
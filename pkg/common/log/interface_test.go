@@ -0,0 +1,75 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// recordingLogger is a fake Interface recording every call
+type recordingLogger struct {
+	fields logrus.Fields
+	calls  *[]string
+}
+
+func newRecordingLogger() recordingLogger {
+	return recordingLogger{fields: logrus.Fields{}, calls: &[]string{}}
+}
+
+func (r recordingLogger) record(level, format string, args ...interface{}) {
+	*r.calls = append(*r.calls, fmt.Sprintf("%s %s %v", level, fmt.Sprintf(format, args...), r.fields))
+}
+
+func (r recordingLogger) Tracef(format string, args ...interface{}) { r.record("trace", format, args...) }
+func (r recordingLogger) Debugf(format string, args ...interface{}) { r.record("debug", format, args...) }
+func (r recordingLogger) Infof(format string, args ...interface{})  { r.record("info", format, args...) }
+func (r recordingLogger) Warnf(format string, args ...interface{})  { r.record("warn", format, args...) }
+func (r recordingLogger) Errorf(format string, args ...interface{}) { r.record("error", format, args...) }
+
+func (r recordingLogger) WithField(key string, value interface{}) Interface {
+	return r.WithFields(logrus.Fields{key: value})
+}
+
+func (r recordingLogger) WithFields(fields logrus.Fields) Interface {
+	merged := logrus.Fields{}
+	for k, v := range r.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return recordingLogger{fields: merged, calls: r.calls}
+}
+
+func (r recordingLogger) WithError(err error) Interface {
+	return r.WithField(logrus.ErrorKey, err)
+}
+
+// install stands in for consumer code accepting the interface
+func install(l Interface, name string) {
+	l.WithField("package", name).Infof("installing %s", name)
+	l.WithError(errors.New("timeout")).Warnf("retrying %s", name)
+}
+
+func TestSetDefault(t *testing.T) {
+	fake := newRecordingLogger()
+	SetDefault(fake)
+	t.Cleanup(func() { SetDefault(nil) })
+
+	install(Default(), "git")
+	assert.Equal(t, []string{
+		"info installing git map[package:git]",
+		"warn retrying git map[error:timeout]",
+	}, *fake.calls)
+}
+
+func TestDefault(t *testing.T) {
+	setFormatter("text")
+	entries := CaptureEntries(func() { install(Default(), "git") })
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "installing git", entries[0].Message)
+	assert.Equal(t, "git", entries[0].Data["package"])
+	assert.Equal(t, logrus.WarnLevel, entries[1].Level)
+}
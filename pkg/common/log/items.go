@@ -0,0 +1,34 @@
+package log
+
+import (
+	"sync/atomic"
+)
+
+// defaultMaxItems is the number of items LogItems renders unless changed with SetMaxItems
+const defaultMaxItems = 50
+
+var maxItems int32 = defaultMaxItems
+
+// SetMaxItems sets the number of items rendered by LogItems before the remaining ones are summarised, 0 renders all
+func SetMaxItems(n int) {
+	if n < 0 {
+		n = 0
+	}
+	atomic.StoreInt32(&maxItems, int32(n))
+}
+
+// LogItems logs a "label (N items):" header followed by each item indented at info level, e.g. to list
+// the installed packages. Items past the maximum set with SetMaxItems are summarised as "(and K more)"
+func LogItems(label string, items []string) {
+	l := Logger()
+	l.Infof("%s (%d %s):", label, len(items), pluralize(len(items), "item", "items"))
+
+	limit := int(atomic.LoadInt32(&maxItems))
+	for i, item := range items {
+		if limit > 0 && i == limit {
+			l.Infof("  (and %d more)", len(items)-limit)
+			return
+		}
+		l.Info("  " + item)
+	}
+}
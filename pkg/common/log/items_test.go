@@ -0,0 +1,31 @@
+package log
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLogItems(t *testing.T) {
+	setFormatter("text")
+	t.Cleanup(func() { SetMaxItems(defaultMaxItems) })
+
+	tests := []struct {
+		name  string
+		max   int
+		items []string
+		want  string
+	}{
+		{"all items", 0, []string{"git", "go", "vscode"}, "INFO: packages (3 items):\nINFO:   git\nINFO:   go\nINFO:   vscode\n"},
+		{"single item", 5, []string{"git"}, "INFO: packages (1 item):\nINFO:   git\n"},
+		{"no items", 5, nil, "INFO: packages (0 items):\n"},
+		{"truncated", 2, []string{"git", "go", "vscode", "7zip"}, "INFO: packages (4 items):\nINFO:   git\nINFO:   go\nINFO:   (and 2 more)\n"},
+		{"exactly max", 2, []string{"git", "go"}, "INFO: packages (2 items):\nINFO:   git\nINFO:   go\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetMaxItems(tt.max)
+			out := CaptureOutput(func() { LogItems("packages", tt.items) })
+			assert.Equal(t, tt.want, out)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package log
+
+import (
+	"sync"
+)
+
+var (
+	knownIssuesMu   sync.Mutex
+	knownIssuesSeen = map[string]bool{}
+)
+
+// LogKnownIssue warns about a known issue or incomplete area the first time it is reached in a run,
+// later calls with the same id log nothing. The id is added as the known_issue field
+func LogKnownIssue(id, msg string) {
+	knownIssuesMu.Lock()
+	seen := knownIssuesSeen[id]
+	knownIssuesSeen[id] = true
+	knownIssuesMu.Unlock()
+	if seen {
+		return
+	}
+	Logger().WithField("known_issue", id).Warn(msg)
+}
@@ -0,0 +1,22 @@
+package log
+
+import (
+	"github.com/Benbentwo/Windows10BootStrapper/pkg/common/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLogKnownIssue(t *testing.T) {
+	setFormatter("text")
+	t.Cleanup(func() { knownIssuesSeen = map[string]bool{} })
+
+	out := CaptureOutput(func() {
+		LogKnownIssue("wsl2", "WSL 2 kernel updates are not installed yet")
+		LogKnownIssue("wsl2", "WSL 2 kernel updates are not installed yet")
+		LogKnownIssue("winget", "winget sources are not configured")
+	})
+	assert.Equal(t, "WARNING: WSL 2 kernel updates are not installed yet\nWARNING: winget sources are not configured\n", out)
+
+	entries := CaptureEntries(func() { LogKnownIssue("store", "store apps are skipped") })
+	logtest.AssertFieldValue(t, entries, "known_issue", "store")
+}
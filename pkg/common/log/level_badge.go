@@ -0,0 +1,43 @@
+package log
+
+import (
+	"fmt"
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+	"sync/atomic"
+)
+
+// badgeWidth fits the longest level label, WARNING
+const badgeWidth = 7
+
+// badgeColors holds the foreground and background of each level badge
+var badgeColors = map[logrus.Level][]color.Attribute{
+	logrus.TraceLevel: {color.FgBlack, color.BgWhite},
+	logrus.DebugLevel: {color.FgBlack, color.BgCyan},
+	logrus.InfoLevel:  {color.FgBlack, color.BgGreen},
+	logrus.WarnLevel:  {color.FgBlack, color.BgYellow},
+	logrus.ErrorLevel: {color.FgHiWhite, color.BgRed},
+	logrus.FatalLevel: {color.FgHiWhite, color.BgRed},
+	logrus.PanicLevel: {color.FgHiWhite, color.BgRed},
+}
+
+var levelBadges int32
+
+// SetLevelBadges renders the level in the text format as a padded badge with a background color
+// e.g. white on red " ERROR " instead of "ERROR:", when colors are disabled only the padded label is rendered
+func SetLevelBadges(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&levelBadges, v)
+}
+
+func levelBadgesEnabled() bool {
+	return atomic.LoadInt32(&levelBadges) == 1
+}
+
+// levelBadge renders the label padded to a fixed width in the badge colors of the level
+func levelBadge(level logrus.Level, label string) string {
+	return color.New(badgeColors[level]...).Sprint(fmt.Sprintf(" %-*s ", badgeWidth, label))
+}
@@ -0,0 +1,35 @@
+package log
+
+import (
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetLevelBadges(t *testing.T) {
+	setFormatter("text")
+	SetLevelBadges(true)
+	noColor := color.NoColor
+	t.Cleanup(func() {
+		SetLevelBadges(false)
+		color.NoColor = noColor
+	})
+
+	tests := []struct {
+		name    string
+		noColor bool
+		log     func()
+		want    string
+	}{
+		{"error badge", false, func() { Logger().Error("install failed") }, "\x1b[97;41m ERROR   \x1b[0m install failed\n"},
+		{"warning badge", false, func() { Logger().Warn("slow") }, "\x1b[30;43m WARNING \x1b[0m slow\n"},
+		{"plain when color is off", true, func() { Logger().Error("install failed") }, " ERROR    install failed\n"},
+		{"plain info when color is off", true, func() { Logger().Info("done") }, " INFO     done\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			color.NoColor = tt.noColor
+			assert.Equal(t, tt.want, CaptureOutput(tt.log))
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package log
+
+import (
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+	"sync"
+)
+
+var (
+	levelColorsMu sync.RWMutex
+
+	// levelColors holds the custom color attributes registered for a level label,
+	// levels without an entry fall back to the default label colors.
+	levelColors = map[logrus.Level][]color.Attribute{}
+)
+
+// severityGradient is the green to yellow to red gradient applied by SetSeverityGradient,
+// ordered from the least to the most severe level.
+var severityGradient = map[logrus.Level][]color.Attribute{
+	logrus.TraceLevel: {color.FgHiGreen},
+	logrus.DebugLevel: {color.FgHiGreen},
+	logrus.InfoLevel:  {color.FgGreen},
+	logrus.WarnLevel:  {color.FgYellow},
+	logrus.ErrorLevel: {color.FgRed},
+	logrus.FatalLevel: {color.FgHiRed},
+	logrus.PanicLevel: {color.FgHiRed, color.Bold},
+}
+
+// SetLevelColor registers the color attributes used to render the label of the given level
+func SetLevelColor(level logrus.Level, attrs ...color.Attribute) {
+	levelColorsMu.Lock()
+	defer levelColorsMu.Unlock()
+	levelColors[level] = attrs
+}
+
+// SetSeverityGradient assigns a green to yellow to red color gradient across the standard levels
+// so each level does not have to be picked manually with SetLevelColor
+func SetSeverityGradient() {
+	for level, attrs := range severityGradient {
+		SetLevelColor(level, attrs...)
+	}
+}
+
+// ResetLevelColors removes all custom level colors, restoring the default label colors
+func ResetLevelColors() {
+	levelColorsMu.Lock()
+	defer levelColorsMu.Unlock()
+	levelColors = map[logrus.Level][]color.Attribute{}
+}
+
+// levelColor returns the custom color attributes registered for the level, if any
+func levelColor(level logrus.Level) ([]color.Attribute, bool) {
+	levelColorsMu.RLock()
+	defer levelColorsMu.RUnlock()
+	attrs, ok := levelColors[level]
+	return attrs, ok
+}
+
+// colorLevel colorizes the label for the given level, preferring any registered custom color
+func colorLevel(level logrus.Level, label string) string {
+	if attrs, ok := levelColor(level); ok {
+		return color.New(attrs...).Sprint(label)
+	}
+	switch level {
+	case logrus.InfoLevel:
+		return colorInfo(label)
+	case logrus.WarnLevel:
+		return colorWarn(label)
+	case logrus.DebugLevel:
+		return colorStatus(label)
+	default:
+		return colorError(label)
+	}
+}
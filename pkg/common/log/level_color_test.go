@@ -0,0 +1,43 @@
+package log
+
+import (
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetSeverityGradient(t *testing.T) {
+	t.Cleanup(ResetLevelColors)
+	SetSeverityGradient()
+
+	tests := []struct {
+		name  string
+		level logrus.Level
+		want  []color.Attribute
+	}{
+		{"info is greenish", logrus.InfoLevel, []color.Attribute{color.FgGreen, color.FgHiGreen}},
+		{"warn is yellowish", logrus.WarnLevel, []color.Attribute{color.FgYellow, color.FgHiYellow}},
+		{"error is reddish", logrus.ErrorLevel, []color.Attribute{color.FgRed, color.FgHiRed}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attrs, ok := levelColor(tt.level)
+			assert.True(t, ok)
+			assert.Subset(t, tt.want, attrs[:1])
+		})
+	}
+}
+
+func TestSetLevelColor(t *testing.T) {
+	t.Cleanup(ResetLevelColors)
+	noColor := color.NoColor
+	color.NoColor = false
+	t.Cleanup(func() { color.NoColor = noColor })
+
+	SetLevelColor(logrus.InfoLevel, color.FgMagenta)
+	assert.Equal(t, color.New(color.FgMagenta).Sprint("INFO"), colorLevel(logrus.InfoLevel, "INFO"))
+
+	ResetLevelColors()
+	assert.Equal(t, colorInfo("INFO"), colorLevel(logrus.InfoLevel, "INFO"))
+}
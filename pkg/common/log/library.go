@@ -0,0 +1,51 @@
+package log
+
+import (
+	"github.com/sirupsen/logrus"
+	"sync"
+)
+
+var (
+	libraryMu       sync.Mutex
+	libraryMode     bool
+	libraryExitFunc func(int)
+)
+
+// SetLibraryMode protects an embedding process when the package is used as a library, with library mode
+// on Fatal still logs at fatal level but returns like Error instead of exiting and the handlers added
+// with RegisterExitHandler are not run. Turning it off restores the previous exit behavior
+func SetLibraryMode(on bool) {
+	libraryMu.Lock()
+	defer libraryMu.Unlock()
+	if on == libraryMode {
+		return
+	}
+	libraryMode = on
+
+	std := logrus.StandardLogger()
+	if on {
+		libraryExitFunc = std.ExitFunc
+		std.ExitFunc = func(int) {}
+		return
+	}
+	std.ExitFunc = libraryExitFunc
+	libraryExitFunc = nil
+}
+
+// RegisterExitHandler adds a handler run before the process exits on Fatal, like
+// logrus.RegisterExitHandler but skipped in library mode as the process does not exit then. Handlers
+// registered with logrus directly always run
+func RegisterExitHandler(handler func()) {
+	logrus.RegisterExitHandler(func() {
+		if !libraryModeOn() {
+			handler()
+		}
+	})
+}
+
+// libraryModeOn reports whether SetLibraryMode turned library mode on
+func libraryModeOn() bool {
+	libraryMu.Lock()
+	defer libraryMu.Unlock()
+	return libraryMode
+}
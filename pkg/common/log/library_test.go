@@ -0,0 +1,34 @@
+package log
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetLibraryMode(t *testing.T) {
+	std := logrus.StandardLogger()
+	exitFunc := std.ExitFunc
+	var exits []int
+	std.ExitFunc = func(code int) { exits = append(exits, code) }
+	handled := 0
+	RegisterExitHandler(func() { handled++ })
+	t.Cleanup(func() {
+		SetLibraryMode(false)
+		std.ExitFunc = exitFunc
+	})
+	setFormatter("text")
+
+	SetLibraryMode(true)
+	SetLibraryMode(true)
+	out := CaptureOutput(func() { Logger().Fatal("disk full") })
+	assert.Equal(t, "FATAL: disk full\n", out)
+	assert.Empty(t, exits)
+	assert.Equal(t, 0, handled)
+
+	SetLibraryMode(false)
+	out = CaptureOutput(func() { Logger().Fatal("disk full") })
+	assert.Equal(t, "FATAL: disk full\n", out)
+	assert.Equal(t, []int{1}, exits)
+	assert.Equal(t, 1, handled)
+}
@@ -2,12 +2,17 @@ package log
 
 import (
 	"bytes"
+	"context"
 	"github.com/fatih/color"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"io"
+	"io/ioutil"
 	"os"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 )
 
 var (
@@ -34,10 +39,43 @@ var (
 
 var ( // For Test Mocks
 	initLogger = initializeLogger
+	now        = time.Now
 )
 
 var defaultLogger *logrus.Logger
 
+// messageStyle renders the plain message of an entry for the text format, e.g. to color it
+type messageStyle func(msg string) string
+
+// messageStyleKey is the context key under which withStyle stores the message style of an entry
+type messageStyleKey struct{}
+
+// withStyle returns the entry with the style attached to its context, the message itself stays plain
+// so hooks, captures and structured formats never see escape sequences
+func withStyle(entry *logrus.Entry, style messageStyle) *logrus.Entry {
+	ctx := entry.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return entry.WithContext(context.WithValue(ctx, messageStyleKey{}, style))
+}
+
+// colorStyle returns a style coloring the whole message with the color function
+func colorStyle(colorFunc func(a ...interface{}) string) messageStyle {
+	return func(msg string) string {
+		return colorFunc(msg)
+	}
+}
+
+// styleOf returns the message style attached to the entry by withStyle, nil if there is none
+func styleOf(entry *logrus.Entry) messageStyle {
+	if entry.Context == nil {
+		return nil
+	}
+	style, _ := entry.Context.Value(messageStyleKey{}).(messageStyle)
+	return style
+}
+
 // FormatLayoutType the layout kind
 type FormatLayoutType string
 
@@ -45,13 +83,20 @@ type FormatLayoutType string
 type CustomTextFormat struct {
 	ShowInfoLevel   bool
 	ShowTimestamp   bool
+	ShowFields      bool
 	TimestampFormat string
+	// FixedWidthTimestamp pads the timestamp to the widest rendering of TimestampFormat so the
+	// following columns always align, it is never truncated
+	FixedWidthTimestamp bool
+
+	compact compactState
 }
 
 func NewCustomTextFormat() *CustomTextFormat {
 	return &CustomTextFormat{
 		ShowInfoLevel:   false,
 		ShowTimestamp:   false,
+		ShowFields:      false,
 		TimestampFormat: "2006-01-02 15:04:05",
 	}
 }
@@ -67,55 +112,130 @@ func (f *CustomTextFormat) Format(entry *logrus.Entry) ([]byte, error) {
 	}
 
 	level := strings.ToUpper(entry.Level.String())
-	switch level {
-	case "INFO":
-		b.WriteString(colorInfo(level))
-		b.WriteString(": ")
-	case "WARNING":
-		b.WriteString(colorWarn(level))
-		b.WriteString(": ")
-	case "DEBUG":
-		b.WriteString(colorStatus(level))
-		b.WriteString(": ")
-	default:
-		b.WriteString(colorError(level))
+	if repeatsPrefix(&f.compact, entry) {
+		b.WriteString(blankPrefix(level))
+	} else if levelBadgesEnabled() {
+		b.WriteString(levelBadge(entry.Level, level))
+		b.WriteByte(' ')
+	} else {
+		b.WriteString(colorLevel(entry.Level, level))
 		b.WriteString(": ")
 	}
 	if f.ShowTimestamp {
-		b.WriteString(entry.Time.Format(f.TimestampFormat))
+		timestamp := entry.Time.Format(f.TimestampFormat)
+		if f.FixedWidthTimestamp {
+			timestamp = fixedWidth(timestamp, timestampWidth(f.TimestampFormat))
+		}
+		b.WriteString(timestamp)
 		b.WriteString(" - ")
 	}
 
-	b.WriteString(entry.Message)
+	msg := entry.Message
+	if style := styleOf(entry); style != nil {
+		msg = style(strings.TrimSuffix(msg, "\n"))
+	} else if isSuccess(entry) {
+		msg = colorInfo(strings.TrimSuffix(msg, "\n"))
+	}
+	if (f.ShowFields || showFieldsEnabled()) && len(entry.Data) > 0 {
+		msg = strings.TrimSuffix(msg, "\n") + renderFields(entry.Data)
+	}
+	b.WriteString(indentPrefix())
+	if category, ok := entry.Data[CategoryField].(string); ok {
+		b.WriteString(colorCategory(category))
+		b.WriteByte(' ')
+	}
+	b.WriteString(msg)
 
-	if !strings.HasSuffix(entry.Message, "\n") {
+	if !strings.HasSuffix(msg, "\n") {
 		b.WriteByte('\n')
 	}
 	return b.Bytes(), nil
 }
 
+// fixedWidth pads s with spaces to at least width runes
+func fixedWidth(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+var timestampWidths sync.Map
+
+// timestampWidth returns the widest rendering of the layout in runes, measured on the last second of
+// a week of days in every month so the longest month and day names, two digit numbers, every fraction
+// digit and the local zone names all count
+func timestampWidth(layout string) int {
+	if width, ok := timestampWidths.Load(layout); ok {
+		return width.(int)
+	}
+	width := 0
+	for month := time.January; month <= time.December; month++ {
+		for day := 20; day <= 26; day++ {
+			t := time.Date(2021, month, day, 23, 59, 59, 999999999, time.Local)
+			if n := utf8.RuneCountInString(t.Format(layout)); n > width {
+				width = n
+			}
+		}
+	}
+	timestampWidths.Store(layout, width)
+	return width
+}
+
 func initializeLogger() error {
 	if logger == nil {
 		var fields logrus.Fields
 		logger = logrus.WithFields(fields)
+		lockOutput()
 
-		format := os.Getenv("LOG_FORMAT")
-		if format == "json" {
-			setFormatter("json")
-		} else {
-			setFormatter("text")
+		warnings := applyDeprecatedEnvVars()
+		setFormatter(FormatLayoutType(os.Getenv("LOG_FORMAT")))
+		if err := loadDefaultConfig(); err != nil {
+			warnings = append(warnings, err.Error())
+		}
+		for _, warning := range warnings {
+			logger.Warn(warning)
 		}
 	}
 	return nil
 }
 
-// setFormatter sets the logrus format to use either text or JSON formatting
+// setFormatter sets the logrus format to use either text, JSON, JSON wrapped text or protobuf formatting
 func setFormatter(layout FormatLayoutType) {
+	installFormatter(layout, newFormatter(layout))
+}
+
+var (
+	formatLayoutMu sync.Mutex
+	formatLayout   FormatLayoutType
+)
+
+// installFormatter sets the formatter of the layout on the default logger, wrapped in the pipeline
+func installFormatter(layout FormatLayoutType, formatter logrus.Formatter) {
+	formatLayoutMu.Lock()
+	defer formatLayoutMu.Unlock()
+	formatLayout = layout
+	logrus.SetFormatter(&pipelineFormatter{Formatter: formatter})
+}
+
+// currentLayout returns the layout of the formatter set with setFormatter
+func currentLayout() FormatLayoutType {
+	formatLayoutMu.Lock()
+	defer formatLayoutMu.Unlock()
+	return formatLayout
+}
+
+// newFormatter returns the formatter for the layout, defaulting to text
+func newFormatter(layout FormatLayoutType) logrus.Formatter {
 	switch layout {
 	case "json":
-		logrus.SetFormatter(&logrus.JSONFormatter{})
+		return &logrus.JSONFormatter{}
+	case "proto":
+		return &ProtoFormatter{}
+	case "json-wrapped":
+		return NewWrappedJSONFormatter()
 	default:
-		logrus.SetFormatter(NewCustomTextFormat())
+		return NewCustomTextFormat()
 	}
 }
 
@@ -126,6 +246,9 @@ func Logger() *logrus.Entry {
 	if err != nil {
 		logrus.Warnf("error initializing logrus %v", err)
 	}
+	if fields := contextFields(); logger != nil && len(fields) > 0 {
+		return logger.WithFields(fields)
+	}
 	return logger
 }
 
@@ -135,22 +258,82 @@ func SetLevel(s string) error {
 	if err != nil {
 		return errors.Errorf("Invalid log level '%s'", s)
 	}
-	logrus.SetLevel(level)
+	setDisplayLevel(level)
 	return nil
 }
 
 // CaptureOutput calls the specified function capturing and returning all logged messages.
 func CaptureOutput(f func()) string {
 	var buf bytes.Buffer
-	logrus.SetOutput(&buf)
+	setOutput(&buf)
 	f()
-	logrus.SetOutput(os.Stderr)
+	setOutput(os.Stderr)
 	return buf.String()
 }
 
+// CaptureEntries calls the specified function capturing and returning all logged entries, the
+// formatted output is discarded. Entries only kept as error context are not captured.
+func CaptureEntries(f func()) []*logrus.Entry {
+	hook := &captureHook{}
+	logrus.AddHook(hook)
+	setOutput(ioutil.Discard)
+	f()
+	setOutput(os.Stderr)
+	removeHook(hook)
+	return hook.entries
+}
+
+// captureHook is a logrus hook keeping a copy of every entry fired
+type captureHook struct {
+	mu      sync.Mutex
+	entries []*logrus.Entry
+}
+
+func (h *captureHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *captureHook) Fire(entry *logrus.Entry) error {
+	if !displayed(entry.Level) {
+		return nil
+	}
+	captured := copyEntry(entry)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.entries = append(h.entries, captured)
+	return nil
+}
+
+// copyEntry returns a copy of the entry with its own fields, safe to keep after the entry is logged
+func copyEntry(entry *logrus.Entry) *logrus.Entry {
+	copied := *entry
+	copied.Buffer = nil
+	copied.Data = make(logrus.Fields, len(entry.Data))
+	for k, v := range entry.Data {
+		copied.Data[k] = v
+	}
+	return &copied
+}
+
+// RenderEntries formats the entries, e.g. from CaptureEntries, with the given formatter and returns the
+// concatenated output, so captured entries can be asserted against several renderings.
+func RenderEntries(entries []*logrus.Entry, f logrus.Formatter) (string, error) {
+	var b strings.Builder
+	for i, entry := range entries {
+		e := *entry
+		e.Buffer = nil
+		serialized, err := f.Format(&e)
+		if err != nil {
+			return "", errors.Wrapf(err, "rendering entry %d", i)
+		}
+		b.Write(serialized)
+	}
+	return b.String(), nil
+}
+
 // SetOutput sets the outputs for the default logger.
 func SetOutput(out io.Writer) {
-	logrus.SetOutput(out)
+	setOutput(out)
 }
 
 // GetLevels returns the list of valid log levels
@@ -5,12 +5,26 @@ import (
 	"errors"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"io/ioutil"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
 
+// TestMain points the default config directory at an empty one so the user config is never loaded
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "log")
+	if err != nil {
+		panic(err)
+	}
+	configDir = dir
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
 func TestCaptureOutput(t *testing.T) {
 	type args struct {
 		f func()
@@ -33,6 +47,43 @@ func TestCaptureOutput(t *testing.T) {
 	}
 }
 
+func TestCaptureEntries(t *testing.T) {
+	hooks := len(logrus.StandardLogger().Hooks[logrus.InfoLevel])
+	entries := CaptureEntries(func() {
+		Logger().WithField("step", 1).Info("abc")
+		Logger().Error("err")
+	})
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "abc", entries[0].Message)
+	assert.Equal(t, logrus.Fields{"step": 1}, entries[0].Data)
+	assert.Equal(t, logrus.ErrorLevel, entries[1].Level)
+	assert.Len(t, logrus.StandardLogger().Hooks[logrus.InfoLevel], hooks)
+}
+
+func TestRenderEntries(t *testing.T) {
+	entries := CaptureEntries(func() {
+		Logger().WithField("step", 1).Info("abc")
+		Logger().Warn("careful")
+	})
+
+	text, err := RenderEntries(entries, NewCustomTextFormat())
+	assert.NoError(t, err)
+	assert.Equal(t, "INFO: abc\nWARNING: careful\n", text)
+
+	json, err := RenderEntries(entries, &logrus.JSONFormatter{DisableTimestamp: true})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"level":"info","msg":"abc","step":1}`+"\n"+`{"level":"warning","msg":"careful"}`+"\n", json)
+
+	_, err = RenderEntries(entries, failingFormatter{})
+	assert.EqualError(t, err, "rendering entry 0: mock format error")
+}
+
+type failingFormatter struct{}
+
+func (failingFormatter) Format(*logrus.Entry) ([]byte, error) {
+	return nil, errors.New("mock format error")
+}
+
 func TestGetLevels(t *testing.T) {
 	tests := []struct {
 		name string
@@ -78,6 +129,7 @@ func TestNewCustomTextFormat(t *testing.T) {
 		{"The Test", &CustomTextFormat{
 			ShowInfoLevel:   false,
 			ShowTimestamp:   false,
+			ShowFields:      false,
 			TimestampFormat: "2006-01-02 15:04:05",
 		}},
 	}
@@ -180,6 +232,32 @@ func TestCustomTextFormat_Format(t *testing.T) {
 	assert.NotNil(t, logs)
 }
 
+func TestCustomTextFormat_FixedWidthTimestamp(t *testing.T) {
+	f := &CustomTextFormat{
+		ShowTimestamp:       true,
+		TimestampFormat:     "Jan _2 15:04:05.999",
+		FixedWidthTimestamp: true,
+	}
+	format := func(ts time.Time) string {
+		out, err := f.Format(&logrus.Entry{Level: logrus.InfoLevel, Time: ts, Message: "ABC"})
+		assert.NoError(t, err)
+		return string(out)
+	}
+
+	short := format(time.Date(2021, 8, 26, 9, 5, 1, 500000000, time.UTC))
+	long := format(time.Date(2021, 8, 26, 9, 5, 1, 123000000, time.UTC))
+	assert.Equal(t, "INFO: Aug 26 09:05:01.5   - ABC\n", short)
+	assert.Equal(t, "INFO: Aug 26 09:05:01.123 - ABC\n", long)
+	assert.Equal(t, strings.Index(short, "ABC"), strings.Index(long, "ABC"))
+
+	f.TimestampFormat = "Monday"
+	assert.Equal(t, "INFO: Wednesday - ABC\n", format(time.Date(2021, 8, 25, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "INFO: Monday    - ABC\n", format(time.Date(2021, 8, 23, 0, 0, 0, 0, time.UTC)))
+
+	f.TimestampFormat = "January 2"
+	assert.Equal(t, "INFO: May 1        - ABC\n", format(time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)))
+}
+
 func TestInitializeLogger(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -211,6 +289,8 @@ func Test_setFormatter(t *testing.T) {
 	}{
 		{"Text", args{layout: FormatLayoutType("text")}},
 		{"Json", args{layout: FormatLayoutType("json")}},
+		{"Proto", args{layout: FormatLayoutType("proto")}},
+		{"JsonWrapped", args{layout: FormatLayoutType("json-wrapped")}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,32 @@
+// Package logtest holds test helpers for code logging through the log package, kept apart so the log
+// package itself does not import testing
+package logtest
+
+import (
+	"github.com/sirupsen/logrus"
+	"reflect"
+	"testing"
+)
+
+// AssertFieldValue fails the test unless one of the entries, e.g. from log.CaptureEntries, has the field
+// key set to want
+func AssertFieldValue(t testing.TB, entries []*logrus.Entry, key string, want interface{}) bool {
+	t.Helper()
+	var seen []interface{}
+	for _, entry := range entries {
+		got, ok := entry.Data[key]
+		if !ok {
+			continue
+		}
+		if reflect.DeepEqual(got, want) {
+			return true
+		}
+		seen = append(seen, got)
+	}
+	if len(seen) == 0 {
+		t.Errorf("no entry has the field '%s', want %#v", key, want)
+	} else {
+		t.Errorf("no entry has the field '%s' set to %#v, got %#v", key, want, seen)
+	}
+	return false
+}
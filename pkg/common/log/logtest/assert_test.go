@@ -0,0 +1,52 @@
+package logtest
+
+import (
+	"fmt"
+	"github.com/Benbentwo/Windows10BootStrapper/pkg/common/log"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// fakeTB records failures instead of failing the test
+type fakeTB struct {
+	testing.TB
+	errors []string
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Errorf(format string, args ...interface{}) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertFieldValue(t *testing.T) {
+	entries := log.CaptureEntries(func() {
+		log.Logger().WithField("package", "git").Info("installing")
+		log.Logger().WithField("attempt", 2).Warn("retrying")
+	})
+
+	tests := []struct {
+		name       string
+		key        string
+		want       interface{}
+		wantPassed bool
+		wantError  string
+	}{
+		{"string value", "package", "git", true, ""},
+		{"int value", "attempt", 2, true, ""},
+		{"wrong value", "package", "curl", false, `no entry has the field 'package' set to "curl", got []interface {}{"git"}`},
+		{"wrong type", "attempt", int64(2), false, `no entry has the field 'attempt' set to 2, got []interface {}{2}`},
+		{"missing field", "version", "1.0", false, `no entry has the field 'version', want "1.0"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tb := &fakeTB{TB: t}
+			assert.Equal(t, tt.wantPassed, AssertFieldValue(tb, entries, tt.key, tt.want))
+			if tt.wantPassed {
+				assert.Empty(t, tb.errors)
+			} else {
+				assert.Equal(t, []string{tt.wantError}, tb.errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package log
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	markersMu sync.Mutex
+	markers   = map[string]time.Time{}
+)
+
+// Mark records the current time under the given name, replacing any previous mark with that name
+func Mark(name string) {
+	markersMu.Lock()
+	defer markersMu.Unlock()
+	markers[name] = now()
+}
+
+// SinceMark returns the time elapsed since the named mark, an unknown mark logs a warning and returns 0
+func SinceMark(name string) time.Duration {
+	elapsed, _ := sinceMark(name)
+	return elapsed
+}
+
+// LogSinceMark logs msg at info level with the time elapsed since the named mark
+func LogSinceMark(name, msg string) {
+	if elapsed, ok := sinceMark(name); ok {
+		Logger().WithField("elapsed", elapsed.String()).Infof("%s (%s)", msg, elapsed)
+	}
+}
+
+func sinceMark(name string) (time.Duration, bool) {
+	markersMu.Lock()
+	marked, ok := markers[name]
+	markersMu.Unlock()
+	if !ok {
+		Logger().Warnf("no mark named '%s'", name)
+		return 0, false
+	}
+	return now().Sub(marked), true
+}
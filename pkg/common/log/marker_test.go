@@ -0,0 +1,54 @@
+package log
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// fakeClock is a controllable replacement for the package clock
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
+// useFakeClock replaces the package clock until the test finishes
+func useFakeClock(t *testing.T) *fakeClock {
+	clock := &fakeClock{t: time.Date(2021, 8, 26, 12, 0, 0, 0, time.UTC)}
+	now = clock.now
+	t.Cleanup(func() { now = time.Now })
+	return clock
+}
+
+func TestLogSinceMark(t *testing.T) {
+	setFormatter("text")
+	clock := useFakeClock(t)
+
+	Mark("install")
+	clock.advance(90 * time.Second)
+	assert.Equal(t, 90*time.Second, SinceMark("install"))
+
+	out := CaptureOutput(func() { LogSinceMark("install", "installed packages") })
+	assert.Equal(t, "INFO: installed packages (1m30s)\n", out)
+
+	entries := CaptureEntries(func() { LogSinceMark("install", "installed packages") })
+	assert.Equal(t, "1m30s", entries[0].Data["elapsed"])
+}
+
+func TestSinceMarkUnknown(t *testing.T) {
+	setFormatter("text")
+	var elapsed time.Duration
+	out := CaptureOutput(func() { elapsed = SinceMark("missing") })
+	assert.Equal(t, time.Duration(0), elapsed)
+	assert.Equal(t, "WARNING: no mark named 'missing'\n", out)
+
+	out = CaptureOutput(func() { LogSinceMark("missing", "never logged") })
+	assert.Equal(t, "WARNING: no mark named 'missing'\n", out)
+}
@@ -0,0 +1,26 @@
+package log
+
+import (
+	"github.com/sirupsen/logrus"
+	"runtime"
+)
+
+// LogMemStats logs the current memory usage as structured fields at debug level, the label names the
+// checkpoint e.g. a bootstrap phase boundary
+func LogMemStats(label string) {
+	if !IsLevelEnabled(logrus.DebugLevel) {
+		return
+	}
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	Logger().WithFields(logrus.Fields{
+		"checkpoint":        label,
+		"alloc_bytes":       m.Alloc,
+		"total_alloc_bytes": m.TotalAlloc,
+		"sys_bytes":         m.Sys,
+		"heap_alloc_bytes":  m.HeapAlloc,
+		"heap_inuse_bytes":  m.HeapInuse,
+		"heap_objects":      m.HeapObjects,
+		"num_gc":            m.NumGC,
+	}).Debugf("memory stats at %s", label)
+}
@@ -0,0 +1,28 @@
+package log
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLogMemStats(t *testing.T) {
+	_ = SetLevel("debug")
+	t.Cleanup(func() { _ = SetLevel("info") })
+
+	entries := CaptureEntries(func() { LogMemStats("after install") })
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	assert.Equal(t, logrus.DebugLevel, entry.Level)
+	assert.Equal(t, "memory stats at after install", entry.Message)
+	assert.Equal(t, "after install", entry.Data["checkpoint"])
+	for _, key := range []string{"alloc_bytes", "total_alloc_bytes", "sys_bytes", "heap_alloc_bytes", "heap_inuse_bytes", "heap_objects", "num_gc"} {
+		assert.Contains(t, entry.Data, key)
+	}
+}
+
+func TestLogMemStatsDisabled(t *testing.T) {
+	_ = SetLevel("info")
+	assert.Empty(t, CaptureEntries(func() { LogMemStats("skipped") }))
+}
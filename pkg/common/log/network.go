@@ -0,0 +1,134 @@
+package log
+
+import (
+	"github.com/pkg/errors"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	networkDialTimeout   = 5 * time.Second
+	networkRetryInterval = time.Second
+)
+
+// networkWriter writes to a tcp or udp socket. After a failed write the connection is redialed in the
+// background, entries written meanwhile are dropped so logging never waits for the collector
+type networkWriter struct {
+	mu          sync.Mutex
+	network     string
+	addr        string
+	conn        net.Conn
+	closed      bool
+	redialing   bool
+	redialDelay time.Duration
+}
+
+// SetNetworkOutput dials addr over tcp or udp and uses the socket as the output of the default logger.
+// When a write fails the connection is dropped and redialed in the background. Closing the returned
+// closer closes the socket and restores the output to stderr
+func SetNetworkOutput(network, addr string) (io.Closer, error) {
+	switch network {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6":
+	default:
+		return nil, errors.Errorf("unsupported network '%s', use tcp or udp", network)
+	}
+	w := &networkWriter{network: network, addr: addr, redialDelay: networkRetryInterval}
+	conn, err := w.dial()
+	if err != nil {
+		return nil, err
+	}
+	w.conn = conn
+	setOutput(w)
+	return w, nil
+}
+
+func (w *networkWriter) dial() (net.Conn, error) {
+	conn, err := net.DialTimeout(w.network, w.addr, networkDialTimeout)
+	if err != nil {
+		return nil, errors.Wrapf(err, "dialing %s %s", w.network, w.addr)
+	}
+	return conn, nil
+}
+
+// Write writes p to the socket, p is dropped while the connection is being redialed
+func (w *networkWriter) Write(p []byte) (int, error) {
+	// suppressed entries format to nothing, over udp they would still send an empty datagram
+	if len(p) == 0 {
+		return 0, nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, errors.New("network output is closed")
+	}
+	if w.conn == nil {
+		w.startRedial()
+		return 0, errors.Errorf("network output %s %s is reconnecting", w.network, w.addr)
+	}
+	n, err := w.conn.Write(p)
+	if err != nil {
+		_ = w.conn.Close()
+		w.conn = nil
+		w.startRedial()
+	}
+	return n, err
+}
+
+// startRedial starts redialing in the background unless it is already running, w.mu must be held
+func (w *networkWriter) startRedial() {
+	if w.redialing {
+		return
+	}
+	w.redialing = true
+	go w.redial()
+}
+
+// redial dials until connected or closed, without holding the lock so writes are never blocked
+func (w *networkWriter) redial() {
+	for {
+		conn, err := w.dial()
+		w.mu.Lock()
+		if w.closed {
+			w.redialing = false
+			w.mu.Unlock()
+			if conn != nil {
+				_ = conn.Close()
+			}
+			return
+		}
+		if err == nil {
+			w.conn = conn
+			w.redialing = false
+			w.mu.Unlock()
+			return
+		}
+		delay := w.redialDelay
+		w.mu.Unlock()
+		time.Sleep(delay)
+	}
+}
+
+// Close closes the socket and restores the output of the default logger to stderr
+func (w *networkWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	conn := w.conn
+	w.conn = nil
+	w.mu.Unlock()
+
+	// the output is swapped without holding w.mu, Write is called under the lock of the logger
+	if currentOutput() == io.Writer(w) {
+		setOutput(os.Stderr)
+	}
+	if conn == nil {
+		return nil
+	}
+	return conn.Close()
+}
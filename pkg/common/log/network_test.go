@@ -0,0 +1,162 @@
+package log
+
+import (
+	"bufio"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+// acceptLines accepts connections on the listener sending every line received to the channel
+func acceptLines(l net.Listener, lines chan<- string) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			scanner := bufio.NewScanner(conn)
+			for scanner.Scan() {
+				lines <- scanner.Text()
+			}
+		}()
+	}
+}
+
+func receive(t *testing.T, lines <-chan string) string {
+	select {
+	case line := <-lines:
+		return line
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for a line")
+		return ""
+	}
+}
+
+func TestSetNetworkOutput(t *testing.T) {
+	setFormatter("text")
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	lines := make(chan string, 10)
+	go acceptLines(l, lines)
+
+	closer, err := SetNetworkOutput("tcp", l.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { SetOutput(os.Stderr) })
+
+	Logger().Info("over the network")
+	assert.Equal(t, "INFO: over the network", receive(t, lines))
+
+	// drop the connection, the failed write is followed by a reconnect on the next one
+	w := closer.(*networkWriter)
+	w.mu.Lock()
+	_ = w.conn.Close()
+	w.mu.Unlock()
+	_, err = w.Write([]byte("lost\n"))
+	assert.Error(t, err)
+	require.Eventually(t, func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return w.conn != nil
+	}, time.Second, 10*time.Millisecond)
+	Logger().Info("reconnected")
+	assert.Equal(t, "INFO: reconnected", receive(t, lines))
+
+	assert.NoError(t, closer.Close())
+	assert.Equal(t, os.Stderr, currentOutput())
+	_, err = w.Write([]byte("closed\n"))
+	assert.Error(t, err)
+}
+
+func TestSetNetworkOutputInvalid(t *testing.T) {
+	_, err := SetNetworkOutput("unix", "/tmp/log.sock")
+	assert.EqualError(t, err, "unsupported network 'unix', use tcp or udp")
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	_ = l.Close()
+	_, err = SetNetworkOutput("tcp", addr)
+	assert.Error(t, err)
+}
+
+func TestNetworkOutputUnavailable(t *testing.T) {
+	setFormatter("text")
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	lines := make(chan string, 10)
+	go acceptLines(l, lines)
+	closer, err := SetNetworkOutput("tcp", l.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { SetOutput(os.Stderr) })
+
+	// with the collector gone writes fail fast instead of waiting for a dial
+	w := closer.(*networkWriter)
+	w.mu.Lock()
+	w.redialDelay = 10 * time.Millisecond
+	_ = w.conn.Close()
+	w.mu.Unlock()
+	_ = l.Close()
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		_, _ = w.Write([]byte("dropped\n"))
+	}
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	assert.NoError(t, closer.Close())
+}
+
+func TestNetworkOutputCloseWhileLogging(t *testing.T) {
+	setFormatter("text")
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	go acceptLines(l, make(chan string, 1000))
+	t.Cleanup(func() { SetOutput(os.Stderr) })
+
+	for i := 0; i < 20; i++ {
+		closer, err := SetNetworkOutput("tcp", l.Addr().String())
+		require.NoError(t, err)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for j := 0; j < 20; j++ {
+				Logger().Info("racing close")
+			}
+		}()
+		assert.NoError(t, closer.Close())
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("logging deadlocked with Close")
+		}
+	}
+}
+
+func TestNetworkOutputSkipsEmptyWrites(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+	closer, err := SetNetworkOutput("udp", conn.LocalAddr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { SetOutput(os.Stderr) })
+
+	w := closer.(*networkWriter)
+	n, err := w.Write(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	_, err = w.Write([]byte("first\n"))
+	require.NoError(t, err)
+
+	// the first datagram received is the non empty write
+	buf := make([]byte, 64)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	n, _, err = conn.ReadFrom(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "first\n", string(buf[:n]))
+	assert.NoError(t, closer.Close())
+}
@@ -0,0 +1,49 @@
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"os"
+	"sync/atomic"
+)
+
+// LogNoNewline logs msg at the given level with the current formatter but without the trailing
+// newline, so the cursor stays on the line e.g. for interactive prompts. The entry is written
+// straight to the output, hooks are not fired
+func LogNoNewline(level logrus.Level, msg string) {
+	if !displayed(level) {
+		return
+	}
+	writeNoNewline(logrus.StandardLogger().Formatter, level, msg, nil)
+}
+
+// writeNoNewline formats msg at the given level with the formatter and writes it without the trailing
+// newline, whatever the configured level. A non-nil style is attached to the entry for the text format.
+// The record terminator is trimmed for every format but proto, which ends with part of its encoding
+func writeNoNewline(formatter logrus.Formatter, level logrus.Level, msg string, style messageStyle) {
+	entry := Logger().WithTime(now())
+	if style != nil {
+		entry = withStyle(entry, style)
+	}
+	entry.Level = level
+	entry.Message = msg
+
+	serialized, err := formatter.Format(entry)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to obtain reader, %v\n", err)
+		return
+	}
+	base := formatter
+	switch f := formatter.(type) {
+	case *pipelineFormatter:
+		base = f.Formatter
+	case promptFormatter:
+		base = baseFormatter()
+	}
+	if _, ok := base.(*ProtoFormatter); !ok {
+		serialized = bytes.TrimSuffix(serialized, []byte{byte(atomic.LoadInt32(&recordSeparator))})
+		serialized = bytes.TrimSuffix(serialized, []byte("\n"))
+	}
+	writeRaw(serialized)
+}
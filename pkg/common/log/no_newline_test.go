@@ -0,0 +1,77 @@
+package log
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLogNoNewline(t *testing.T) {
+	setFormatter("text")
+	tests := []struct {
+		name  string
+		level logrus.Level
+		msg   string
+		want  string
+	}{
+		{"info", logrus.InfoLevel, "Continue? [y/N] ", "INFO: Continue? [y/N] "},
+		{"trailing newline", logrus.WarnLevel, "careful\n", "WARNING: careful"},
+		{"disabled level", logrus.DebugLevel, "hidden", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := CaptureOutput(func() { LogNoNewline(tt.level, tt.msg) })
+			assert.Equal(t, tt.want, out)
+		})
+	}
+}
+
+func TestLogNoNewlineProto(t *testing.T) {
+	setFormatter("proto")
+	t.Cleanup(func() { setFormatter("text") })
+	useFakeClock(t)
+
+	out := CaptureOutput(func() { LogNoNewline(logrus.InfoLevel, "careful\n") })
+	records, err := DecodeRecords(strings.NewReader(out))
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "careful\n", records[0].Message)
+	assert.True(t, now().Equal(records[0].Timestamp))
+}
+
+func TestLogNoNewlineJSON(t *testing.T) {
+	setFormatter("json")
+	t.Cleanup(func() { setFormatter("text") })
+
+	out := CaptureOutput(func() { LogNoNewline(logrus.InfoLevel, "Continue?") })
+	assert.True(t, strings.HasPrefix(out, "{"))
+	assert.True(t, strings.HasSuffix(out, "}"))
+}
+
+func TestLogNoNewlineConcurrent(t *testing.T) {
+	setFormatter("text")
+	var wg sync.WaitGroup
+	out := CaptureOutput(func() {
+		for i := 0; i < 4; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 20; j++ {
+					LogNoNewline(logrus.InfoLevel, "partial")
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 20; j++ {
+					Logger().Info("line")
+				}
+			}()
+		}
+		wg.Wait()
+	})
+	assert.Equal(t, 4*20, strings.Count(out, "INFO: partial"))
+	assert.Equal(t, 4*20, strings.Count(out, "INFO: line\n"))
+}
@@ -0,0 +1,54 @@
+package log
+
+import (
+	"crypto/rand"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"sync"
+)
+
+// OperationIDField is the field tying together all entries of a single run
+const OperationIDField = "op_id"
+
+var (
+	operationMu sync.RWMutex
+	operationID string
+)
+
+// SetOperationID attaches the id as the op_id field of every entry so all logs of one run can be
+// correlated, an empty id generates a random UUID
+func SetOperationID(id string) {
+	if id == "" {
+		id = newUUID()
+	}
+	operationMu.Lock()
+	defer operationMu.Unlock()
+	operationID = id
+}
+
+// OperationID returns the id set with SetOperationID, empty when none is set
+func OperationID() string {
+	operationMu.RLock()
+	defer operationMu.RUnlock()
+	return operationID
+}
+
+// operationFields returns the op_id field, empty when no operation id is set
+func operationFields() logrus.Fields {
+	id := OperationID()
+	if id == "" {
+		return nil
+	}
+	return logrus.Fields{OperationIDField: id}
+}
+
+// newUUID returns a random version 4 UUID
+func newUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
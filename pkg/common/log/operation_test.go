@@ -0,0 +1,48 @@
+package log
+
+import (
+	"github.com/Benbentwo/Windows10BootStrapper/pkg/common/log/logtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+)
+
+func TestSetOperationID(t *testing.T) {
+	t.Cleanup(func() { clearOperationID() })
+
+	SetOperationID("bootstrap-42")
+	entries := CaptureEntries(func() {
+		Logger().Info("starting")
+		Logger().WithField("step", "git").Info("installing")
+		Logger().Warn("done with warnings")
+	})
+	require.Len(t, entries, 3)
+	for _, entry := range entries {
+		assert.Equal(t, "bootstrap-42", entry.Data[OperationIDField])
+	}
+}
+
+func TestSetOperationIDGenerated(t *testing.T) {
+	t.Cleanup(func() { clearOperationID() })
+
+	SetOperationID("")
+	id := OperationID()
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), id)
+
+	entries := CaptureEntries(func() {
+		Logger().Info("one")
+		Logger().Info("two")
+	})
+	logtest.AssertFieldValue(t, entries[:1], OperationIDField, id)
+	logtest.AssertFieldValue(t, entries[1:], OperationIDField, id)
+
+	SetOperationID("")
+	assert.NotEqual(t, id, OperationID())
+}
+
+func clearOperationID() {
+	operationMu.Lock()
+	defer operationMu.Unlock()
+	operationID = ""
+}
@@ -0,0 +1,73 @@
+package log
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"io"
+	"os"
+	"sync"
+)
+
+var (
+	outputMu sync.Mutex
+	output   *lockedOutput
+)
+
+// lockedOutput is the output of the default logger, every write takes the lock shared with the sinks
+// writing to the same writer so writes bypassing the logger never race or interleave with entries
+type lockedOutput struct {
+	w  io.Writer
+	mu *sync.Mutex
+}
+
+// Write writes p to the writer holding its lock
+func (o *lockedOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.w.Write(p)
+}
+
+// setOutput sets w as the output of the default logger
+func setOutput(w io.Writer) {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	previous := output
+	output = &lockedOutput{w: w, mu: sinkLock(w)}
+	logrus.SetOutput(output)
+	if previous != nil {
+		releaseSinkLock(previous.w)
+	}
+}
+
+// lockOutput wraps the output of the default logger unless it was set with setOutput already
+func lockOutput() {
+	outputMu.Lock()
+	set := output != nil
+	outputMu.Unlock()
+	if !set {
+		setOutput(logrus.StandardLogger().Out)
+	}
+}
+
+// currentOutput returns the writer set with setOutput, or the output of the default logger before
+func currentOutput() io.Writer {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	if output == nil {
+		return logrus.StandardLogger().Out
+	}
+	return output.w
+}
+
+// writeRaw writes already formatted bytes to the output of the default logger
+func writeRaw(b []byte) {
+	outputMu.Lock()
+	var out io.Writer = output
+	if output == nil {
+		out = logrus.StandardLogger().Out
+	}
+	outputMu.Unlock()
+	if _, err := out.Write(b); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write to log, %v\n", err)
+	}
+}
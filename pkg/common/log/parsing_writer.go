@@ -0,0 +1,128 @@
+package log
+
+import (
+	"bytes"
+	"github.com/sirupsen/logrus"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultLevelTokens maps the line prefixes recognised by a ParsingWriter to their level, fatal and
+// panic lines are logged as errors so the output of another program can never exit this one
+var defaultLevelTokens = map[string]logrus.Level{
+	"[TRACE]":   logrus.TraceLevel,
+	"[DEBUG]":   logrus.DebugLevel,
+	"[INFO]":    logrus.InfoLevel,
+	"[WARN]":    logrus.WarnLevel,
+	"[WARNING]": logrus.WarnLevel,
+	"[ERROR]":   logrus.ErrorLevel,
+	"[FATAL]":   logrus.ErrorLevel,
+	"[PANIC]":   logrus.ErrorLevel,
+}
+
+// ParsingWriterOptions configures how a ParsingWriter maps lines to levels
+type ParsingWriterOptions struct {
+	// DefaultLevel is used for lines without a recognised prefix
+	DefaultLevel logrus.Level
+	// Tokens maps additional line prefixes to a level, e.g. "[NOTICE]" to info, overriding the defaults
+	Tokens map[string]logrus.Level
+}
+
+// ParsingWriter is an io.Writer logging every written line at the level named by its prefix e.g.
+// "[WARN] low disk space", useful to forward the output of external tools through the logger
+type ParsingWriter struct {
+	mu           sync.Mutex
+	buf          []byte
+	defaultLevel logrus.Level
+	tokens       []string
+	levels       map[string]logrus.Level
+	// forceLevel logs every line at the default level, a recognised prefix is still removed
+	forceLevel bool
+}
+
+// NewParsingWriter creates a ParsingWriter logging lines without a recognised prefix at info level
+func NewParsingWriter() *ParsingWriter {
+	return NewParsingWriterWithDefault(logrus.InfoLevel)
+}
+
+// NewParsingWriterWithDefault creates a ParsingWriter logging lines without a recognised prefix at the given level
+func NewParsingWriterWithDefault(level logrus.Level) *ParsingWriter {
+	return NewParsingWriterWithOptions(ParsingWriterOptions{DefaultLevel: level})
+}
+
+// NewParsingWriterWithOptions creates a ParsingWriter using the given options
+func NewParsingWriterWithOptions(opts ParsingWriterOptions) *ParsingWriter {
+	w := &ParsingWriter{
+		defaultLevel: opts.DefaultLevel,
+		levels:       map[string]logrus.Level{},
+	}
+	for token, level := range defaultLevelTokens {
+		w.levels[token] = level
+	}
+	for token, level := range opts.Tokens {
+		w.levels[strings.ToUpper(token)] = level
+	}
+	for token := range w.levels {
+		w.tokens = append(w.tokens, token)
+	}
+	// longest first so a token is never shadowed by one of its prefixes
+	sort.Slice(w.tokens, func(i, j int) bool {
+		if len(w.tokens[i]) != len(w.tokens[j]) {
+			return len(w.tokens[i]) > len(w.tokens[j])
+		}
+		return w.tokens[i] < w.tokens[j]
+	})
+	return w
+}
+
+// Write logs every complete line in p, a trailing partial line is kept until it is completed or flushed
+func (w *ParsingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.logLine(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush logs any buffered partial line
+func (w *ParsingWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		w.logLine(string(w.buf))
+		w.buf = nil
+	}
+}
+
+// logLine logs a single line at the level of its prefix, with the prefix removed
+func (w *ParsingWriter) logLine(line string) {
+	line = strings.TrimRight(line, "\r")
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" {
+		return
+	}
+	level, msg := w.parse(trimmed)
+	if w.forceLevel {
+		level = w.defaultLevel
+	}
+	Logger().Log(level, msg)
+}
+
+// parse returns the level of the token the line starts with, ignoring case, and the rest of the line.
+// The prefix of the line itself is compared as upper casing may change its length
+func (w *ParsingWriter) parse(line string) (logrus.Level, string) {
+	for _, token := range w.tokens {
+		if len(line) >= len(token) && strings.EqualFold(line[:len(token)], token) {
+			return w.levels[token], strings.TrimSpace(line[len(token):])
+		}
+	}
+	return w.defaultLevel, line
+}
@@ -0,0 +1,47 @@
+package log
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNewParsingWriter(t *testing.T) {
+	setFormatter("text")
+	out := CaptureOutput(func() {
+		w := NewParsingWriter()
+		_, _ = fmt.Fprint(w, "[WARN] low disk space\nplain line\n[error] failed\n\n[FATAL] bad")
+		w.Flush()
+	})
+	assert.Equal(t, "WARNING: low disk space\nINFO: plain line\nERROR: failed\nERROR: bad\n", out)
+}
+
+func TestNewParsingWriterWithOptions(t *testing.T) {
+	setFormatter("text")
+	tests := []struct {
+		name   string
+		writer *ParsingWriter
+		input  string
+		want   string
+	}{
+		{"custom default", NewParsingWriterWithDefault(logrus.WarnLevel), "no prefix\n", "WARNING: no prefix\n"},
+		{"custom token", NewParsingWriterWithOptions(ParsingWriterOptions{
+			DefaultLevel: logrus.ErrorLevel,
+			Tokens:       map[string]logrus.Level{"[NOTICE]": logrus.InfoLevel},
+		}), "[NOTICE] reboot required\nno prefix\n", "INFO: reboot required\nERROR: no prefix\n"},
+		{"override default token", NewParsingWriterWithOptions(ParsingWriterOptions{
+			DefaultLevel: logrus.InfoLevel,
+			Tokens:       map[string]logrus.Level{"[error]": logrus.WarnLevel},
+		}), "[ERROR] not so bad\n", "WARNING: not so bad\n"},
+		{"case changing length", NewParsingWriter(), "[ınfo] kept\n[Warn] mixed case\n", "INFO: [ınfo] kept\nWARNING: mixed case\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := CaptureOutput(func() {
+				_, _ = fmt.Fprint(tt.writer, tt.input)
+			})
+			assert.Equal(t, tt.want, out)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package log
+
+import (
+	"github.com/sirupsen/logrus"
+	"sync"
+)
+
+// maxPausedDeliveries bounds the deliveries buffered while hooks are paused, the oldest are dropped first
+const maxPausedDeliveries = 1024
+
+// HookPauseMode decides what happens to hook deliveries while hooks are paused
+type HookPauseMode int
+
+const (
+	// DropWhilePaused discards deliveries while hooks are paused
+	DropWhilePaused HookPauseMode = iota
+	// BufferWhilePaused keeps deliveries while hooks are paused and fires them on ResumeHooks
+	BufferWhilePaused
+)
+
+type pausedDelivery struct {
+	hook  logrus.Hook
+	entry *logrus.Entry
+}
+
+var (
+	pauseMu          sync.Mutex
+	hooksPaused      bool
+	hookPauseMode    HookPauseMode
+	pausedDeliveries []pausedDelivery
+)
+
+// PauseHooks stops delivering entries to the hooks registered with AddHook, e.g. remote shipping
+// during a noisy bulk operation. The output of the default logger and sinks continue
+func PauseHooks() {
+	pauseMu.Lock()
+	defer pauseMu.Unlock()
+	hooksPaused = true
+}
+
+// ResumeHooks delivers entries to the hooks again, firing the deliveries buffered while paused first
+func ResumeHooks() {
+	pauseMu.Lock()
+	deliveries := pausedDeliveries
+	pausedDeliveries = nil
+	hooksPaused = false
+	pauseMu.Unlock()
+
+	for _, d := range deliveries {
+		if err := d.hook.Fire(d.entry); err != nil {
+			Logger().Warnf("failed to fire paused hook: %v", err)
+		}
+	}
+}
+
+// SetHookPauseMode sets whether deliveries are dropped, the default, or buffered while hooks are paused
+func SetHookPauseMode(mode HookPauseMode) {
+	pauseMu.Lock()
+	defer pauseMu.Unlock()
+	hookPauseMode = mode
+}
+
+// holdDelivery reports whether the delivery of the entry to the hook is held back as hooks are paused,
+// buffering a copy of the entry if configured
+func holdDelivery(hook logrus.Hook, entry *logrus.Entry) bool {
+	pauseMu.Lock()
+	defer pauseMu.Unlock()
+	if !hooksPaused {
+		return false
+	}
+	if hookPauseMode == BufferWhilePaused {
+		if len(pausedDeliveries) == maxPausedDeliveries {
+			pausedDeliveries = pausedDeliveries[1:]
+		}
+		pausedDeliveries = append(pausedDeliveries, pausedDelivery{hook: hook, entry: copyEntry(entry)})
+	}
+	return true
+}
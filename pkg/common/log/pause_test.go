@@ -0,0 +1,84 @@
+package log
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// webhookHook posts the message of every entry to a URL
+type webhookHook struct {
+	url string
+}
+
+func (h *webhookHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *webhookHook) Fire(entry *logrus.Entry) error {
+	resp, err := http.Post(h.url, "text/plain", strings.NewReader(entry.Message))
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// webhookServer returns a server recording the bodies posted to it
+func webhookServer(t *testing.T) (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, string(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), received...)
+	}
+}
+
+func TestPauseHooks(t *testing.T) {
+	tests := []struct {
+		name string
+		mode HookPauseMode
+		want []string
+	}{
+		{"drop", DropWhilePaused, []string{"before", "after"}},
+		{"buffer", BufferWhilePaused, []string{"before", "during", "after"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restoreHooks(t)
+			t.Cleanup(func() {
+				ResumeHooks()
+				SetHookPauseMode(DropWhilePaused)
+			})
+			setFormatter("text")
+			server, received := webhookServer(t)
+			AddHook("webhook", &webhookHook{url: server.URL})
+			SetHookPauseMode(tt.mode)
+
+			out := CaptureOutput(func() {
+				Logger().Info("before")
+				PauseHooks()
+				Logger().Info("during")
+				assert.Equal(t, []string{"before"}, received())
+				ResumeHooks()
+				Logger().Info("after")
+			})
+			assert.Equal(t, "INFO: before\nINFO: during\nINFO: after\n", out)
+			assert.Equal(t, tt.want, received())
+		})
+	}
+}
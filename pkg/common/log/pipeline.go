@@ -0,0 +1,58 @@
+package log
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+// pipelineFormatter wraps the formatter selected with setFormatter, suppressed entries are dropped
+// before they are formatted so nothing is written for them and the fields of the others are
+// prepared the same way for every format
+type pipelineFormatter struct {
+	logrus.Formatter
+}
+
+// Format formats the log statement unless it is suppressed, an error is preceded by its error context
+func (p *pipelineFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	if suppressed(entry) {
+		return nil, nil
+	}
+	context, held := withErrorContext(entry)
+	if held {
+		return nil, nil
+	}
+	return p.format(entry, context)
+}
+
+// format formats the entry preceded by its error context, without suppressing anything
+func (p *pipelineFormatter) format(entry *logrus.Entry, context []*logrus.Entry) ([]byte, error) {
+	formatter, notice := autoJSON.formatter(p.Formatter, entry)
+
+	var records []*logrus.Entry
+	if notice != nil {
+		records = append(records, notice)
+	}
+	records = append(append(records, context...), entry)
+	var b []byte
+	for _, e := range records {
+		serialized, err := formatter.Format(prepareEntry(e))
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, terminateRecord(formatter, serialized)...)
+	}
+	return b, nil
+}
+
+// prepareEntry returns the entry with its fields prepared the same way for every format
+func prepareEntry(entry *logrus.Entry) *logrus.Entry {
+	return anonymizeEntry(sanitizeEntry(truncateFieldValues(entry)))
+}
+
+// baseFormatter returns the formatter of the default logger without the pipeline around it
+func baseFormatter() logrus.Formatter {
+	formatter := logrus.StandardLogger().Formatter
+	if p, ok := formatter.(*pipelineFormatter); ok {
+		return p.Formatter
+	}
+	return formatter
+}
@@ -0,0 +1,29 @@
+package log
+
+import (
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+)
+
+// colorPrompt returns prompt-colorized (bold magenta) strings for the given arguments with fmt.Sprint()
+var colorPrompt = color.New(color.FgMagenta, color.Bold).SprintFunc()
+
+// LogPrompt writes the question for an interactive step in the prompt color without a trailing
+// newline, so the input follows on the same line. The prompt is prepared like every entry but written
+// straight to the output whatever the level and is never held back by the message cooldown
+func LogPrompt(question string) {
+	writeNoNewline(promptFormatter{}, logrus.InfoLevel, question, colorStyle(colorPrompt))
+}
+
+// promptFormatter formats prompts through the pipeline of the default logger, skipping the cooldown
+// and the error context so a prompt is always shown
+type promptFormatter struct{}
+
+// Format formats the entry with the pipeline of the default logger, or its formatter without one
+func (promptFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	formatter := logrus.StandardLogger().Formatter
+	if p, ok := formatter.(*pipelineFormatter); ok {
+		return p.format(entry, nil)
+	}
+	return formatter.Format(prepareEntry(entry))
+}
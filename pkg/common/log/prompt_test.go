@@ -0,0 +1,40 @@
+package log
+
+import (
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLogPrompt(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	t.Cleanup(func() {
+		color.NoColor = noColor
+		_ = SetLevel("info")
+		SetMessageCooldown(0)
+	})
+	setFormatter("text")
+
+	out := CaptureOutput(func() { LogPrompt("Install WSL? [y/N] ") })
+	assert.Equal(t, colorInfo("INFO")+": \x1b[35;1mInstall WSL? [y/N] \x1b[0m", out)
+
+	_ = SetLevel("error")
+	SetMessageCooldown(time.Minute)
+	out = CaptureOutput(func() {
+		LogPrompt("Continue?")
+		LogPrompt("Continue?")
+	})
+	assert.Equal(t, colorInfo("INFO")+": \x1b[35;1mContinue?\x1b[0m"+colorInfo("INFO")+": \x1b[35;1mContinue?\x1b[0m", out)
+}
+
+func TestLogPromptAnonymized(t *testing.T) {
+	setFormatter("text")
+	t.Cleanup(func() { atomic.StoreInt32(&anonymizePaths, 0) })
+
+	EnablePathAnonymization()
+	out := CaptureOutput(func() { LogPrompt(`Overwrite C:\Users\alice\.gitconfig? [y/N] `) })
+	assert.Equal(t, `INFO: Overwrite C:\Users\<user>\.gitconfig? [y/N] `, out)
+}
@@ -0,0 +1,181 @@
+package log
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/encoding/protowire"
+	"io"
+	"sort"
+	"time"
+)
+
+// LogRecord field numbers, see log_record.proto
+const (
+	recordLevelField     protowire.Number = 1
+	recordTimestampField protowire.Number = 2
+	recordMessageField   protowire.Number = 3
+	recordFieldsField    protowire.Number = 4
+
+	mapKeyField   protowire.Number = 1
+	mapValueField protowire.Number = 2
+
+	// maxRecordSize guards against allocating huge buffers for a corrupt length prefix
+	maxRecordSize = 64 << 20
+)
+
+// LogRecord is a single entry of the binary log format written by ProtoFormatter
+type LogRecord struct {
+	Level     string
+	Timestamp time.Time
+	Message   string
+	Fields    map[string]string
+}
+
+// ProtoFormatter formats each entry as a length prefixed protobuf LogRecord, for binary log pipelines
+// read back with a decoder. Field values are stored as strings
+type ProtoFormatter struct{}
+
+// Format formats the log statement
+func (f *ProtoFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	record := LogRecord{
+		Level:     entry.Level.String(),
+		Timestamp: entry.Time,
+		Message:   entry.Message,
+		Fields:    make(map[string]string, len(entry.Data)),
+	}
+	for k, v := range entry.Data {
+		if err, ok := v.(error); ok {
+			record.Fields[k] = err.Error()
+		} else {
+			record.Fields[k] = fmt.Sprint(v)
+		}
+	}
+	body := record.marshal()
+	return append(protowire.AppendVarint(nil, uint64(len(body))), body...), nil
+}
+
+// DecodeRecords reads a stream written by ProtoFormatter back into records. On a truncated or corrupt
+// stream the records decoded so far are returned together with the error
+func DecodeRecords(r io.Reader) ([]LogRecord, error) {
+	br := bufio.NewReader(r)
+	var records []LogRecord
+	for {
+		size, err := binary.ReadUvarint(br)
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			if err == io.ErrUnexpectedEOF {
+				return records, errors.Errorf("truncated length prefix of record %d", len(records)+1)
+			}
+			return records, errors.Wrapf(err, "invalid length prefix of record %d", len(records)+1)
+		}
+		if size > maxRecordSize {
+			return records, errors.Errorf("corrupt record %d: length %d exceeds %d bytes", len(records)+1, size, maxRecordSize)
+		}
+
+		body := make([]byte, size)
+		if n, err := io.ReadFull(br, body); err != nil {
+			return records, errors.Errorf("truncated record %d: read %d of %d bytes", len(records)+1, n, size)
+		}
+		var record LogRecord
+		if err := record.unmarshal(body); err != nil {
+			return records, errors.Wrapf(err, "corrupt record %d", len(records)+1)
+		}
+		records = append(records, record)
+	}
+}
+
+// marshal encodes the record in the protobuf wire format, fields are sorted so the output is stable
+func (r *LogRecord) marshal() []byte {
+	var b []byte
+	b = protowire.AppendTag(b, recordLevelField, protowire.BytesType)
+	b = protowire.AppendString(b, r.Level)
+	b = protowire.AppendTag(b, recordTimestampField, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(r.Timestamp.UnixNano()))
+	b = protowire.AppendTag(b, recordMessageField, protowire.BytesType)
+	b = protowire.AppendString(b, r.Message)
+
+	keys := make([]string, 0, len(r.Fields))
+	for k := range r.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		var kv []byte
+		kv = protowire.AppendTag(kv, mapKeyField, protowire.BytesType)
+		kv = protowire.AppendString(kv, k)
+		kv = protowire.AppendTag(kv, mapValueField, protowire.BytesType)
+		kv = protowire.AppendString(kv, r.Fields[k])
+		b = protowire.AppendTag(b, recordFieldsField, protowire.BytesType)
+		b = protowire.AppendBytes(b, kv)
+	}
+	return b
+}
+
+// unmarshal decodes a record from the protobuf wire format, unknown fields are skipped
+func (r *LogRecord) unmarshal(b []byte) error {
+	r.Fields = map[string]string{}
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return errors.Wrap(protowire.ParseError(n), "invalid record tag")
+		}
+		b = b[n:]
+
+		switch {
+		case num == recordLevelField && typ == protowire.BytesType:
+			r.Level, n = protowire.ConsumeString(b)
+		case num == recordTimestampField && typ == protowire.VarintType:
+			var ts uint64
+			ts, n = protowire.ConsumeVarint(b)
+			r.Timestamp = time.Unix(0, int64(ts))
+		case num == recordMessageField && typ == protowire.BytesType:
+			r.Message, n = protowire.ConsumeString(b)
+		case num == recordFieldsField && typ == protowire.BytesType:
+			var kv []byte
+			kv, n = protowire.ConsumeBytes(b)
+			if n >= 0 {
+				if err := r.unmarshalField(kv); err != nil {
+					return err
+				}
+			}
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return errors.Wrapf(protowire.ParseError(n), "invalid record field %d", num)
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+// unmarshalField decodes a single key value entry of the fields map
+func (r *LogRecord) unmarshalField(b []byte) error {
+	var key, value string
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return errors.Wrap(protowire.ParseError(n), "invalid field tag")
+		}
+		b = b[n:]
+		switch {
+		case num == mapKeyField && typ == protowire.BytesType:
+			key, n = protowire.ConsumeString(b)
+		case num == mapValueField && typ == protowire.BytesType:
+			value, n = protowire.ConsumeString(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return errors.Wrap(protowire.ParseError(n), "invalid field entry")
+		}
+		b = b[n:]
+	}
+	r.Fields[key] = value
+	return nil
+}
@@ -0,0 +1,96 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+	"testing"
+	"time"
+)
+
+func TestProtoFormatter_Format(t *testing.T) {
+	setFormatter("proto")
+	t.Cleanup(func() { setFormatter("text") })
+
+	ts := time.Date(2021, 8, 26, 12, 30, 0, 123, time.UTC)
+	out := CaptureOutput(func() {
+		Logger().WithTime(ts).WithFields(logrus.Fields{
+			"package": "git",
+			"attempt": 2,
+			"error":   errors.New("timeout"),
+		}).Warn("install failed")
+	})
+
+	size, n := protowire.ConsumeVarint([]byte(out))
+	require.True(t, n > 0)
+	require.Equal(t, int(size), len(out)-n)
+
+	var record LogRecord
+	require.NoError(t, record.unmarshal([]byte(out[n:])))
+	assert.Equal(t, "warning", record.Level)
+	assert.True(t, ts.Equal(record.Timestamp))
+	assert.Equal(t, "install failed", record.Message)
+	assert.Equal(t, map[string]string{"package": "git", "attempt": "2", "error": "timeout"}, record.Fields)
+}
+
+func TestLogRecord_unmarshalInvalid(t *testing.T) {
+	var record LogRecord
+	assert.Error(t, record.unmarshal([]byte{0x0a, 0x05, 'a'}))
+}
+
+func TestDecodeRecords(t *testing.T) {
+	setFormatter("proto")
+	t.Cleanup(func() { setFormatter("text") })
+
+	out := CaptureOutput(func() {
+		Logger().WithField("step", "download").Info("downloading")
+		Logger().Warn("slow mirror")
+		Logger().WithField("step", "verify").Error("checksum mismatch")
+	})
+
+	records, err := DecodeRecords(bytes.NewBufferString(out))
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, "downloading", records[0].Message)
+	assert.Equal(t, map[string]string{"step": "download"}, records[0].Fields)
+	assert.Equal(t, "warning", records[1].Level)
+	assert.Empty(t, records[1].Fields)
+	assert.Equal(t, "error", records[2].Level)
+	assert.Equal(t, "verify", records[2].Fields["step"])
+
+	empty, err := DecodeRecords(&bytes.Buffer{})
+	assert.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+func TestDecodeRecordsInvalid(t *testing.T) {
+	setFormatter("proto")
+	t.Cleanup(func() { setFormatter("text") })
+	out := CaptureOutput(func() {
+		Logger().Info("first")
+		Logger().Info("second")
+	})
+
+	tests := []struct {
+		name    string
+		stream  []byte
+		decoded int
+		wantErr string
+	}{
+		{"truncated record", []byte(out[:len(out)-3]), 1, "truncated record 2"},
+		{"truncated prefix", append([]byte(out), 0x80), 2, "truncated length prefix of record 3"},
+		{"oversized", protowire.AppendVarint(nil, maxRecordSize+1), 0, "corrupt record 1"},
+		{"corrupt body", []byte{0x02, 0x0a, 0x05}, 0, "corrupt record 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := DecodeRecords(bytes.NewReader(tt.stream))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Len(t, records, tt.decoded)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package log
+
+import (
+	"github.com/sirupsen/logrus"
+	"sync/atomic"
+)
+
+var recordSeparator int32 = '\n'
+
+// SetRecordSeparator terminates every entry written to the output and sinks with sep instead of a
+// newline, e.g. 0 for NUL delimited records. Newlines within a message are kept, the protobuf format
+// is length prefixed and never changed
+func SetRecordSeparator(sep byte) {
+	atomic.StoreInt32(&recordSeparator, int32(sep))
+}
+
+// terminateRecord replaces the trailing newline of the entry formatted by the formatter with the
+// record separator
+func terminateRecord(formatter logrus.Formatter, serialized []byte) []byte {
+	sep := byte(atomic.LoadInt32(&recordSeparator))
+	if sep == '\n' || len(serialized) == 0 || serialized[len(serialized)-1] != '\n' {
+		return serialized
+	}
+	if _, ok := formatter.(*ProtoFormatter); ok {
+		return serialized
+	}
+	serialized[len(serialized)-1] = sep
+	return serialized
+}
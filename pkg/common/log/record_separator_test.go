@@ -0,0 +1,37 @@
+package log
+
+import (
+	"bytes"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetRecordSeparator(t *testing.T) {
+	restoreHooks(t)
+	t.Cleanup(func() {
+		SetRecordSeparator('\n')
+		setFormatter("text")
+	})
+	setFormatter("text")
+	audit := &bytes.Buffer{}
+	AddSink("audit", audit, "json")
+	SetRecordSeparator(0)
+
+	out := CaptureOutput(func() {
+		Logger().Info("first")
+		Logger().Warn("multi\nline")
+		LogNoNewline(logrus.InfoLevel, "Continue? ")
+	})
+	assert.Equal(t, "INFO: first\x00WARNING: multi\nline\x00INFO: Continue? ", out)
+	records := bytes.Split(bytes.TrimSuffix(audit.Bytes(), []byte{0}), []byte{0})
+	assert.Len(t, records, 2)
+	assert.Contains(t, string(records[1]), `"msg":"multi\nline"`)
+	assert.NotContains(t, audit.String(), "\n")
+
+	setFormatter("proto")
+	proto := []byte(CaptureOutput(func() { Logger().Info("first") }))
+	decoded, err := DecodeRecords(bytes.NewReader(proto))
+	assert.NoError(t, err)
+	assert.Len(t, decoded, 1)
+}
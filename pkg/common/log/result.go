@@ -0,0 +1,11 @@
+package log
+
+// LogResult logs the outcome of a check like a test runner, "PASS name" in green at info level when err
+// is nil or "FAIL name: err" in red at error level otherwise, with result and error fields
+func LogResult(name string, err error) {
+	if err == nil {
+		withStyle(Logger().WithField("result", "pass"), colorStyle(colorInfo)).Info("PASS " + name)
+		return
+	}
+	withStyle(Logger().WithField("result", "fail").WithError(err), colorStyle(colorError)).Error("FAIL " + name + ": " + err.Error())
+}
@@ -0,0 +1,49 @@
+package log
+
+import (
+	"github.com/fatih/color"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLogResult(t *testing.T) {
+	setFormatter("text")
+	noColor := color.NoColor
+	color.NoColor = false
+	t.Cleanup(func() { color.NoColor = noColor })
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"pass", nil, colorInfo("INFO") + ": " + colorInfo("PASS git installed") + "\n"},
+		{"fail", errors.New("not on PATH"), colorError("ERROR") + ": " + colorError("FAIL git installed: not on PATH") + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := CaptureOutput(func() { LogResult("git installed", tt.err) })
+			assert.Equal(t, tt.want, out)
+		})
+	}
+}
+
+func TestLogResultFields(t *testing.T) {
+	setFormatter("json")
+	t.Cleanup(func() { setFormatter("text") })
+	err := errors.New("not on PATH")
+
+	entries := CaptureEntries(func() {
+		LogResult("git installed", nil)
+		LogResult("git installed", err)
+	})
+	require.Len(t, entries, 2)
+	assert.Equal(t, logrus.Fields{"result": "pass"}, entries[0].Data)
+	assert.Equal(t, "PASS git installed", entries[0].Message)
+	assert.Equal(t, logrus.ErrorLevel, entries[1].Level)
+	assert.Equal(t, logrus.Fields{"result": "fail", "error": err}, entries[1].Data)
+	assert.Equal(t, "FAIL git installed: not on PATH", entries[1].Message)
+}
@@ -0,0 +1,97 @@
+package log
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// csiSequence matches any ANSI control sequence at the start of a string, e.g. a color or moving the cursor
+var csiSequence = regexp.MustCompile("^\x1b\\[[0-9;?]*[ -/]*[@-~]")
+
+var sanitizeInput int32
+
+// SetSanitizeInput escapes control characters in messages and string field values, so user supplied
+// strings cannot forge log lines: newlines, including the unicode line and paragraph separators, are
+// rendered escaped and ANSI sequences are stripped, colors included as the package colors the output
+// itself. Errors and fmt.Stringer values are sanitized as strings. Off by default, as multi-line
+// messages are rendered on a single line when on
+func SetSanitizeInput(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&sanitizeInput, v)
+}
+
+// sanitizeEntry returns the entry with its message and string field values sanitized when enabled,
+// the given entry is left untouched as its data may be shared with other entries
+func sanitizeEntry(entry *logrus.Entry) *logrus.Entry {
+	if atomic.LoadInt32(&sanitizeInput) == 0 {
+		return entry
+	}
+	sanitized := *entry
+	if strings.HasSuffix(entry.Message, "\n") {
+		sanitized.Message = sanitize(strings.TrimSuffix(entry.Message, "\n")) + "\n"
+	} else {
+		sanitized.Message = sanitize(entry.Message)
+	}
+	sanitized.Data = make(logrus.Fields, len(entry.Data))
+	for k, v := range entry.Data {
+		sanitized.Data[k] = sanitizeValue(v)
+	}
+	return &sanitized
+}
+
+// sanitizeValue sanitizes strings, errors and fmt.Stringer values are replaced by their sanitized
+// text only when it differs so they keep their type otherwise
+func sanitizeValue(v interface{}) interface{} {
+	var s string
+	switch value := v.(type) {
+	case string:
+		return sanitize(value)
+	case error:
+		s = value.Error()
+	case fmt.Stringer:
+		s = value.String()
+	default:
+		return v
+	}
+	if sanitized := sanitize(s); sanitized != s {
+		return sanitized
+	}
+	return v
+}
+
+// sanitize escapes the control characters of s except tabs and strips ANSI sequences
+func sanitize(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == '\x1b':
+			if seq := csiSequence.FindString(s[i:]); seq != "" {
+				size = len(seq)
+			} else {
+				b.WriteString(`\x1b`)
+			}
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteRune(r)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, r)
+		case r == '\u0085', r == '\u2028', r == '\u2029':
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package log
+
+import (
+	"github.com/fatih/color"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetSanitizeInput(t *testing.T) {
+	logrus.SetFormatter(&pipelineFormatter{Formatter: &CustomTextFormat{ShowFields: true}})
+	t.Cleanup(func() {
+		SetSanitizeInput(false)
+		setFormatter("text")
+	})
+	forged := "package git\nERROR: disk wiped\x1b[1A\x1b[2K"
+
+	out := CaptureOutput(func() { Logger().WithField("name", forged).Info(forged) })
+	assert.Equal(t, "INFO: package git\nERROR: disk wiped\x1b[1A\x1b[2K name=\"package git\\nERROR: disk wiped\\x1b[1A\\x1b[2K\"\n", out)
+
+	SetSanitizeInput(true)
+	out = CaptureOutput(func() { Logger().WithField("name", forged).Info(forged) })
+	assert.Equal(t, `INFO: package git\nERROR: disk wiped name="package git\\nERROR: disk wiped"`+"\n", out)
+
+	out = CaptureOutput(func() {
+		Logger().WithError(errors.New(forged)).WithField("path", forgedPath(forged)).Info("failed")
+	})
+	assert.Equal(t, `INFO: failed error="package git\\nERROR: disk wiped" path="package git\\nERROR: disk wiped"`+"\n", out)
+}
+
+// forgedPath is a fmt.Stringer field value
+type forgedPath string
+
+func (p forgedPath) String() string {
+	return string(p)
+}
+
+func Test_sanitizeValue(t *testing.T) {
+	err := errors.New("disk wiped")
+	assert.Equal(t, err, sanitizeValue(err))
+	assert.Equal(t, forgedPath("C:\\tools"), sanitizeValue(forgedPath("C:\\tools")))
+	assert.Equal(t, "a\\nb", sanitizeValue(errors.New("a\nb")))
+	assert.Equal(t, 3, sanitizeValue(3))
+}
+
+func Test_sanitize(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	t.Cleanup(func() { color.NoColor = noColor })
+
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{"plain", "installed git", "installed git"},
+		{"newlines", "a\r\nb", `a\r\nb`},
+		{"tab", "a\tb", "a\tb"},
+		{"control", "a\x00b\x7f", `a\x00b\x7f`},
+		{"unicode newlines", "a\u0085b\u2028c\u2029d", `a\u0085b\u2028c\u2029d`},
+		{"unicode kept", "café ✓", "café ✓"},
+		{"invalid utf8 kept", "a\xffb", "a\xffb"},
+		{"color stripped", colorInfo("done"), "done"},
+		{"reset stripped", "\x1b[31ma\x1b[0m\x1b[m", "a"},
+		{"extended colors stripped", "\x1b[38;5;208ma\x1b[48;2;0;128;255mb", "ab"},
+		{"conceal stripped", "a\x1b[8mb\x1b[31;8mc", "abc"},
+		{"bold and blink stripped", "a\x1b[1mb\x1b[5mc", "abc"},
+		{"malformed extended color stripped", "a\x1b[38;5mb\x1b[38;2;300;0;0mc", "abc"},
+		{"cursor stripped", "a\x1b[1Ab\x1b[?25l", "ab"},
+		{"lone escape", "a\x1bb", `a\x1bb`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sanitize(tt.s))
+		})
+	}
+}
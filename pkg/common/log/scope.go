@@ -0,0 +1,96 @@
+package log
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"sync"
+)
+
+var (
+	scopeMu    sync.RWMutex
+	scopeStack []logrus.Fields
+)
+
+// scopeKey is the context key under which PushContextFields keeps the fields of the scopes
+type scopeKey struct{}
+
+// PushFields opens a scope adding the fields to every entry obtained from Logger() until the matching
+// PopFields, inner scopes override the fields of outer ones. The stack is shared by all goroutines, use
+// PushContextFields for scopes of concurrent goroutines
+func PushFields(fields logrus.Fields) {
+	scope := make(logrus.Fields, len(fields))
+	for k, v := range fields {
+		scope[k] = v
+	}
+	scopeMu.Lock()
+	defer scopeMu.Unlock()
+	scopeStack = append(scopeStack, scope)
+}
+
+// PopFields closes the innermost scope opened with PushFields
+func PopFields() {
+	scopeMu.Lock()
+	defer scopeMu.Unlock()
+	if len(scopeStack) > 0 {
+		scopeStack = scopeStack[:len(scopeStack)-1]
+	}
+}
+
+// scopeFields returns the fields of every open scope, empty when none is open
+func scopeFields() logrus.Fields {
+	scopeMu.RLock()
+	defer scopeMu.RUnlock()
+	if len(scopeStack) == 0 {
+		return nil
+	}
+	fields := logrus.Fields{}
+	for _, scope := range scopeStack {
+		for k, v := range scope {
+			fields[k] = v
+		}
+	}
+	return fields
+}
+
+// PushContextFields returns a copy of ctx opening a scope that adds the fields to every entry obtained
+// from ScopedLogger with it or a context derived from it, inner scopes override the fields of outer
+// ones. The scope closes with the context, so goroutines only get the scopes of the context they were
+// given
+func PushContextFields(ctx context.Context, fields logrus.Fields) context.Context {
+	outer := contextScopeFields(ctx)
+	scope := make(logrus.Fields, len(outer)+len(fields))
+	for k, v := range outer {
+		scope[k] = v
+	}
+	for k, v := range fields {
+		scope[k] = v
+	}
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// ScopedLogger returns the logger obtained from Logger() with the context attached and the fields of
+// every scope opened in it with PushContextFields, which override those of PushFields. The sub-command
+// and operation fields take precedence
+func ScopedLogger(ctx context.Context) *logrus.Entry {
+	entry := Logger().WithContext(ctx)
+	scope := contextScopeFields(ctx)
+	if len(scope) == 0 {
+		return entry
+	}
+	fields := make(logrus.Fields, len(scope))
+	for k, v := range scope {
+		fields[k] = v
+	}
+	for _, provided := range []logrus.Fields{subCommandFields(), operationFields()} {
+		for k, v := range provided {
+			fields[k] = v
+		}
+	}
+	return entry.WithFields(fields)
+}
+
+// contextScopeFields returns the fields of every scope opened in ctx, empty when none is open
+func contextScopeFields(ctx context.Context) logrus.Fields {
+	fields, _ := ctx.Value(scopeKey{}).(logrus.Fields)
+	return fields
+}
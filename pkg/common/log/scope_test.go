@@ -0,0 +1,129 @@
+package log
+
+import (
+	"context"
+	"github.com/Benbentwo/Windows10BootStrapper/pkg/common/log/logtest"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+)
+
+func TestPushFields(t *testing.T) {
+	entries := CaptureEntries(func() {
+		Logger().Info("before")
+		PushFields(logrus.Fields{"package": "git", "step": "download"})
+		Logger().Info("outer")
+		PushFields(logrus.Fields{"step": "install"})
+		Logger().WithField("attempt", 1).Info("inner")
+		PopFields()
+		Logger().Info("outer again")
+		PopFields()
+		PopFields()
+		Logger().Info("after")
+	})
+	require.Len(t, entries, 5)
+	assert.Empty(t, entries[0].Data)
+	assert.Equal(t, logrus.Fields{"package": "git", "step": "download"}, entries[1].Data)
+	assert.Equal(t, logrus.Fields{"package": "git", "step": "install", "attempt": 1}, entries[2].Data)
+	assert.Equal(t, logrus.Fields{"package": "git", "step": "download"}, entries[3].Data)
+	assert.Empty(t, entries[4].Data)
+}
+
+func TestPushFieldsCopiesFields(t *testing.T) {
+	t.Cleanup(PopFields)
+	fields := logrus.Fields{"package": "git"}
+	PushFields(fields)
+	fields["package"] = "changed"
+
+	entries := CaptureEntries(func() { Logger().Info("installed") })
+	logtest.AssertFieldValue(t, entries, "package", "git")
+}
+
+func TestPushFieldsConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	entries := CaptureEntries(func() {
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				PushFields(logrus.Fields{"worker": i})
+				Logger().Info("working")
+				PopFields()
+			}(i)
+		}
+		wg.Wait()
+	})
+	assert.Len(t, entries, 8)
+	assert.Empty(t, scopeFields())
+}
+
+func TestPushContextFields(t *testing.T) {
+	entries := CaptureEntries(func() {
+		ctx := context.Background()
+		ScopedLogger(ctx).Info("before")
+		outer := PushContextFields(ctx, logrus.Fields{"package": "git", "step": "download"})
+		ScopedLogger(outer).Info("outer")
+		inner := PushContextFields(outer, logrus.Fields{"step": "install"})
+		ScopedLogger(inner).WithField("attempt", 1).Info("inner")
+		ScopedLogger(outer).Info("outer again")
+		ScopedLogger(ctx).Info("after")
+		Logger().Info("unscoped")
+	})
+	require.Len(t, entries, 6)
+	assert.Empty(t, entries[0].Data)
+	assert.Equal(t, logrus.Fields{"package": "git", "step": "download"}, entries[1].Data)
+	assert.Equal(t, logrus.Fields{"package": "git", "step": "install", "attempt": 1}, entries[2].Data)
+	assert.Equal(t, logrus.Fields{"package": "git", "step": "download"}, entries[3].Data)
+	assert.Empty(t, entries[4].Data)
+	assert.Empty(t, entries[5].Data)
+}
+
+func TestPushContextFieldsCopiesFields(t *testing.T) {
+	fields := logrus.Fields{"package": "git"}
+	ctx := PushContextFields(context.Background(), fields)
+	fields["package"] = "changed"
+
+	entries := CaptureEntries(func() { ScopedLogger(ctx).Info("installed") })
+	logtest.AssertFieldValue(t, entries, "package", "git")
+}
+
+func TestPushContextFieldsSubCommandPrecedence(t *testing.T) {
+	ctx := PushContextFields(context.Background(), logrus.Fields{SubCommandField: "scope", "package": "git"})
+	PushSubCommand("deploy")
+	t.Cleanup(PopSubCommand)
+
+	entries := CaptureEntries(func() { ScopedLogger(ctx).Info("deploying") })
+	logtest.AssertFieldValue(t, entries, SubCommandField, "deploy")
+	logtest.AssertFieldValue(t, entries, "package", "git")
+}
+
+func TestPushContextFieldsConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	entries := CaptureEntries(func() {
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				ctx := PushContextFields(context.Background(), logrus.Fields{"worker": i})
+				ScopedLogger(ctx).WithField("logged_by", i).Info("working")
+			}(i)
+		}
+		wg.Wait()
+	})
+	require.Len(t, entries, 8)
+	for _, entry := range entries {
+		assert.Equal(t, logrus.Fields{"worker": entry.Data["logged_by"], "logged_by": entry.Data["logged_by"]}, entry.Data)
+	}
+}
+
+func TestPushContextFieldsOverridePushFields(t *testing.T) {
+	PushFields(logrus.Fields{"step": "download", "package": "git"})
+	t.Cleanup(PopFields)
+	ctx := PushContextFields(context.Background(), logrus.Fields{"step": "install"})
+
+	entries := CaptureEntries(func() { ScopedLogger(ctx).Info("installing") })
+	require.Len(t, entries, 1)
+	assert.Equal(t, logrus.Fields{"step": "install", "package": "git"}, entries[0].Data)
+}
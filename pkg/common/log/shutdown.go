@@ -0,0 +1,48 @@
+package log
+
+import (
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+var ( // For Test Mocks
+	fallbackOutput io.Writer = os.Stderr
+)
+
+var (
+	shutdownMu    sync.Mutex
+	shutdownFuncs []func()
+	hasShutDown   int32
+)
+
+// onShutdown registers a function run by Shutdown, used to stop background workers
+func onShutdown(f func()) {
+	shutdownMu.Lock()
+	defer shutdownMu.Unlock()
+	shutdownFuncs = append(shutdownFuncs, f)
+}
+
+// Shutdown stops the background workers started by the package, it should be called before the process exits.
+// Anything logged afterwards is written as text to stderr only, hooks and sinks are no longer fired.
+// The output is swapped before the workers are stopped so nothing is written to what they close
+func Shutdown() {
+	atomic.StoreInt32(&hasShutDown, 1)
+	setOutput(fallbackOutput)
+	setFormatter("text")
+
+	shutdownMu.Lock()
+	funcs := shutdownFuncs
+	shutdownFuncs = nil
+	shutdownMu.Unlock()
+
+	for i := len(funcs) - 1; i >= 0; i-- {
+		funcs[i]()
+	}
+}
+
+// isShutDown reports whether Shutdown was called
+func isShutDown() bool {
+	return atomic.LoadInt32(&hasShutDown) == 1
+}
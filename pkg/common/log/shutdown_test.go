@@ -0,0 +1,74 @@
+package log
+
+import (
+	"bytes"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"sync/atomic"
+	"testing"
+)
+
+// restoreShutdown marks the package as running again once the test finishes
+func restoreShutdown(t *testing.T) {
+	t.Cleanup(func() {
+		atomic.StoreInt32(&hasShutDown, 0)
+		SetOutput(os.Stderr)
+		setFormatter("text")
+	})
+}
+
+// closedWriter fails every write like a closed file or socket would
+type closedWriter struct {
+	writes int
+}
+
+func (w *closedWriter) Write([]byte) (int, error) {
+	w.writes++
+	return 0, os.ErrClosed
+}
+
+func TestLogAfterShutdown(t *testing.T) {
+	restoreHooks(t)
+	restoreShutdown(t)
+	fallback := &bytes.Buffer{}
+	output := fallbackOutput
+	fallbackOutput = fallback
+	t.Cleanup(func() { fallbackOutput = output })
+
+	setFormatter("json")
+	sink := &closedWriter{}
+	AddSink("audit", sink, "json")
+	webhook := &countingHook{}
+	AddHook("webhook", webhook)
+	stopped := false
+	onShutdown(func() {
+		stopped = true
+		Logger().Info("closing")
+	})
+
+	Shutdown()
+	assert.True(t, stopped)
+	assert.NotPanics(t, func() {
+		Logger().Info("after shutdown")
+		Shutdown()
+		Logger().Warn("still running")
+	})
+	assert.Equal(t, "INFO: closing\nINFO: after shutdown\nWARNING: still running\n", fallback.String())
+	assert.Zero(t, sink.writes)
+	assert.Zero(t, webhook.fired)
+}
+
+// countingHook counts the entries fired
+type countingHook struct {
+	fired int
+}
+
+func (h *countingHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *countingHook) Fire(*logrus.Entry) error {
+	h.fired++
+	return nil
+}
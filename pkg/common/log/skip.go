@@ -0,0 +1,24 @@
+package log
+
+import (
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+)
+
+// colorMuted returns muted-colorized (grey) strings for the given arguments with fmt.Sprint()
+var colorMuted = color.New(color.FgHiBlack).SprintFunc()
+
+// LogSkip logs a step that was already satisfied as "⊘ skipped what (reason)" in grey at info level,
+// with skipped, step and reason fields
+func LogSkip(what string, reason string) {
+	fields := logrus.Fields{
+		"skipped": true,
+		"step":    what,
+	}
+	msg := "⊘ skipped " + what
+	if reason != "" {
+		fields["reason"] = reason
+		msg += " (" + reason + ")"
+	}
+	withStyle(Logger().WithFields(fields), colorStyle(colorMuted)).Info(msg)
+}
@@ -0,0 +1,45 @@
+package log
+
+import (
+	"encoding/json"
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLogSkip(t *testing.T) {
+	setFormatter("text")
+	noColor := color.NoColor
+	color.NoColor = false
+	t.Cleanup(func() { color.NoColor = noColor })
+
+	tests := []struct {
+		name   string
+		what   string
+		reason string
+		want   string
+	}{
+		{"with reason", "install git", "already installed", colorInfo("INFO") + ": " + colorMuted("⊘ skipped install git (already installed)") + "\n"},
+		{"without reason", "enable WSL", "", colorInfo("INFO") + ": " + colorMuted("⊘ skipped enable WSL") + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := CaptureOutput(func() { LogSkip(tt.what, tt.reason) })
+			assert.Equal(t, tt.want, out)
+		})
+	}
+}
+
+func TestLogSkipJSON(t *testing.T) {
+	setFormatter("json")
+	t.Cleanup(func() { setFormatter("text") })
+
+	out := CaptureOutput(func() { LogSkip("install git", "already installed") })
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(out), &got))
+	assert.Equal(t, true, got["skipped"])
+	assert.Equal(t, "install git", got["step"])
+	assert.Equal(t, "already installed", got["reason"])
+	assert.Equal(t, "⊘ skipped install git (already installed)", got["msg"])
+}
@@ -0,0 +1,63 @@
+package log
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"strings"
+	"sync/atomic"
+)
+
+var maxStackFrames int64
+
+// stackTracer is implemented by the errors of github.com/pkg/errors carrying a stack trace
+type stackTracer interface {
+	StackTrace() errors.StackTrace
+}
+
+// SetMaxStackFrames limits the frames rendered of a stack trace to the top n, noting how many were
+// left out. 0, the default, renders all frames
+func SetMaxStackFrames(n int) {
+	atomic.StoreInt64(&maxStackFrames, int64(n))
+}
+
+// LogErrorStack logs the error at error level followed by the stack trace recorded where it was
+// created by github.com/pkg/errors, if any
+func LogErrorStack(err error) {
+	msg := err.Error()
+	if stack := renderStack(err); stack != "" {
+		msg += "\n" + stack
+	}
+	Logger().WithError(err).Error(msg)
+}
+
+// renderStack renders the stack trace of the innermost error in the cause chain carrying one, one
+// "function\n\tfile:line" frame per line pair
+func renderStack(err error) string {
+	var trace errors.StackTrace
+	for err != nil {
+		if tracer, ok := err.(stackTracer); ok {
+			trace = tracer.StackTrace()
+		}
+		cause, ok := err.(interface{ Cause() error })
+		if !ok {
+			break
+		}
+		err = cause.Cause()
+	}
+	if len(trace) == 0 {
+		return ""
+	}
+
+	frames := trace
+	if limit := int(atomic.LoadInt64(&maxStackFrames)); limit > 0 && len(frames) > limit {
+		frames = frames[:limit]
+	}
+	lines := make([]string, 0, len(frames)+1)
+	for _, frame := range frames {
+		lines = append(lines, fmt.Sprintf("%+v", frame))
+	}
+	if omitted := len(trace) - len(frames); omitted > 0 {
+		lines = append(lines, fmt.Sprintf("…(%d more frames)", omitted))
+	}
+	return strings.Join(lines, "\n")
+}
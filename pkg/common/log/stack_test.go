@@ -0,0 +1,65 @@
+package log
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// deepError returns an error created depth calls down
+func deepError(depth int) error {
+	if depth == 0 {
+		return errors.New("disk full")
+	}
+	return deepError(depth - 1)
+}
+
+func TestSetMaxStackFrames(t *testing.T) {
+	t.Cleanup(func() { SetMaxStackFrames(0) })
+	err := errors.Wrap(deepError(10), "installing")
+	total := len(errors.Cause(err).(stackTracer).StackTrace())
+
+	tests := []struct {
+		name   string
+		max    int
+		frames int
+		note   string
+	}{
+		{"all frames", 0, total, ""},
+		{"top frames", 3, 3, "…(" + strconv.Itoa(total-3) + " more frames)"},
+		{"limit above depth", total + 5, total, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetMaxStackFrames(tt.max)
+			lines := strings.Split(renderStack(err), "\n")
+			frames := lines
+			if tt.note != "" {
+				frames = lines[:len(lines)-1]
+				assert.Equal(t, tt.note, lines[len(lines)-1])
+			}
+			assert.Len(t, frames, 2*tt.frames)
+			assert.Contains(t, frames[0], "log.deepError")
+		})
+	}
+}
+
+func TestLogErrorStack(t *testing.T) {
+	t.Cleanup(func() { SetMaxStackFrames(0) })
+	setFormatter("text")
+	SetMaxStackFrames(1)
+
+	out := CaptureOutput(func() { LogErrorStack(deepError(5)) })
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	assert.Len(t, lines, 4)
+	assert.Equal(t, "ERROR: disk full", lines[0])
+	assert.Contains(t, lines[1], "log.deepError")
+	assert.Contains(t, lines[2], "stack_test.go:")
+	assert.True(t, strings.HasPrefix(lines[3], "…("))
+
+	out = CaptureOutput(func() { LogErrorStack(fmt.Errorf("no stack")) })
+	assert.Equal(t, "ERROR: no stack\n", out)
+}
@@ -0,0 +1,128 @@
+package log
+
+import (
+	"github.com/sirupsen/logrus"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var ( // For Test Mocks
+	newTicker = func(d time.Duration) ticker {
+		return &timeTicker{time.NewTicker(d)}
+	}
+)
+
+var (
+	counter   = &levelCounter{}
+	counterMu sync.Mutex
+
+	statsMu   sync.Mutex
+	statsStop chan struct{}
+)
+
+// ticker is the part of time.Ticker used by the periodic stats, so tests can drive the ticks
+type ticker interface {
+	Chan() <-chan time.Time
+	Stop()
+}
+
+type timeTicker struct {
+	*time.Ticker
+}
+
+func (t *timeTicker) Chan() <-chan time.Time {
+	return t.C
+}
+
+// levelCounter is a logrus hook counting the entries logged at each level
+type levelCounter struct {
+	counts [logrus.TraceLevel + 1]uint64
+}
+
+// Levels returns the levels the hook fires for
+func (c *levelCounter) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire increments the counter of the entry level, entries only kept as error context are not counted
+func (c *levelCounter) Fire(entry *logrus.Entry) error {
+	if entry.Level <= logrus.TraceLevel && displayed(entry.Level) {
+		atomic.AddUint64(&c.counts[entry.Level], 1)
+	}
+	return nil
+}
+
+func (c *levelCounter) count(level logrus.Level) uint64 {
+	return atomic.LoadUint64(&c.counts[level])
+}
+
+func (c *levelCounter) reset() {
+	for i := range c.counts {
+		atomic.StoreUint64(&c.counts[i], 0)
+	}
+}
+
+// ensureLevelCounter registers the level counter hook unless it is already registered. It is
+// registered on logrus directly so pausing the hooks never stops the counting
+func ensureLevelCounter() {
+	counterMu.Lock()
+	defer counterMu.Unlock()
+	if !hasHook(counter) {
+		logrus.AddHook(counter)
+	}
+}
+
+// LevelCounts returns the number of entries logged per level since counting was enabled
+func LevelCounts() map[string]uint64 {
+	counts := make(map[string]uint64, len(logrus.AllLevels))
+	for _, level := range logrus.AllLevels {
+		counts[level.String()] = counter.count(level)
+	}
+	return counts
+}
+
+// EnablePeriodicStats logs the current per level counts at debug level every interval until Shutdown
+// is called, calling it again replaces the previous interval and a non positive interval disables it
+func EnablePeriodicStats(interval time.Duration) {
+	ensureLevelCounter()
+
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	if statsStop != nil {
+		close(statsStop)
+		statsStop = nil
+	}
+	if interval <= 0 {
+		return
+	}
+
+	stop := make(chan struct{})
+	statsStop = stop
+	t := newTicker(interval)
+	go func() {
+		defer t.Stop()
+		for {
+			select {
+			case <-t.Chan():
+				logStats()
+			case <-stop:
+				return
+			}
+		}
+	}()
+	onShutdown(func() {
+		statsMu.Lock()
+		defer statsMu.Unlock()
+		if statsStop == stop {
+			close(statsStop)
+			statsStop = nil
+		}
+	})
+}
+
+// logStats logs a single line with the current per level counts
+func logStats() {
+	Logger().Debugf("stats: info=%d warn=%d error=%d",
+		counter.count(logrus.InfoLevel), counter.count(logrus.WarnLevel), counter.count(logrus.ErrorLevel))
+}
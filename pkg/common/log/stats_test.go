@@ -0,0 +1,109 @@
+package log
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+	"time"
+)
+
+type fakeTicker struct {
+	c       chan time.Time
+	stopped chan struct{}
+}
+
+func (f *fakeTicker) Chan() <-chan time.Time {
+	return f.c
+}
+
+func (f *fakeTicker) Stop() {
+	close(f.stopped)
+}
+
+// lineWriter sends every written line to a channel so output from other goroutines can be awaited
+type lineWriter chan string
+
+func (w lineWriter) Write(p []byte) (int, error) {
+	w <- string(p)
+	return len(p), nil
+}
+
+func TestEnablePeriodicStats(t *testing.T) {
+	restoreHooks(t)
+	fake := &fakeTicker{c: make(chan time.Time), stopped: make(chan struct{})}
+	tickerFunc := newTicker
+	newTicker = func(time.Duration) ticker { return fake }
+	t.Cleanup(func() {
+		newTicker = tickerFunc
+		SetOutput(os.Stderr)
+		_ = SetLevel("info")
+	})
+
+	setFormatter("text")
+	_ = SetLevel("debug")
+	lines := make(lineWriter, 10)
+	SetOutput(lines)
+
+	EnablePeriodicStats(time.Minute)
+	counter.reset()
+	Logger().Info("one")
+	Logger().Info("two")
+	Logger().Warn("three")
+	Logger().Error("four")
+	for i := 0; i < 4; i++ {
+		<-lines
+	}
+
+	fake.c <- time.Now()
+	select {
+	case line := <-lines:
+		assert.Equal(t, "DEBUG: stats: info=2 warn=1 error=1\n", line)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the stats line")
+	}
+
+	restoreShutdown(t)
+	Shutdown()
+	select {
+	case <-fake.stopped:
+	case <-time.After(time.Second):
+		t.Fatal("ticker was not stopped by Shutdown")
+	}
+	assert.Equal(t, uint64(1), LevelCounts()["warning"])
+}
+
+// restoreHooks restores the hooks registered on the standard logger once the test finishes
+func restoreHooks(t *testing.T) {
+	hooks := make(logrus.LevelHooks)
+	for level, levelHooks := range logrus.StandardLogger().Hooks {
+		hooks[level] = append(hooks[level], levelHooks...)
+	}
+	t.Cleanup(func() {
+		logrus.StandardLogger().ReplaceHooks(hooks)
+	})
+}
+
+func TestLevelCounts(t *testing.T) {
+	restoreHooks(t)
+	ensureLevelCounter()
+	counter.reset()
+	_ = CaptureOutput(func() {
+		Logger().Error("failed")
+	})
+	counts := LevelCounts()
+	assert.Equal(t, uint64(1), counts["error"])
+	assert.Equal(t, uint64(0), counts["info"])
+}
+
+func TestLevelCountsWhileHooksPaused(t *testing.T) {
+	restoreHooks(t)
+	ensureLevelCounter()
+	counter.reset()
+	PauseHooks()
+	_ = CaptureOutput(func() {
+		Logger().Warn("slow mirror")
+	})
+	ResumeHooks()
+	assert.Equal(t, uint64(1), LevelCounts()["warning"])
+}
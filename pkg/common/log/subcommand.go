@@ -0,0 +1,55 @@
+package log
+
+import (
+	"github.com/sirupsen/logrus"
+	"strings"
+	"sync"
+)
+
+const (
+	// SubCommandField is the field holding the active sub-commands joined by spaces
+	SubCommandField = "subcommand"
+
+	// SubCommandStackField is the field holding the active sub-commands as an array, outermost first
+	SubCommandStackField = "subcommand_stack"
+)
+
+var (
+	subCommandMu    sync.RWMutex
+	subCommandStack []string
+)
+
+// PushSubCommand marks the named sub-command as active, nesting it under any already active sub-command
+func PushSubCommand(name string) {
+	subCommandMu.Lock()
+	defer subCommandMu.Unlock()
+	subCommandStack = append(subCommandStack, name)
+}
+
+// PopSubCommand removes the innermost active sub-command
+func PopSubCommand() {
+	subCommandMu.Lock()
+	defer subCommandMu.Unlock()
+	if len(subCommandStack) > 0 {
+		subCommandStack = subCommandStack[:len(subCommandStack)-1]
+	}
+}
+
+// SubCommands returns the active sub-commands, outermost first
+func SubCommands() []string {
+	subCommandMu.RLock()
+	defer subCommandMu.RUnlock()
+	return append([]string(nil), subCommandStack...)
+}
+
+// subCommandFields returns the sub-command fields, empty when no sub-command is active
+func subCommandFields() logrus.Fields {
+	stack := SubCommands()
+	if len(stack) == 0 {
+		return nil
+	}
+	return logrus.Fields{
+		SubCommandField:      strings.Join(stack, " "),
+		SubCommandStackField: stack,
+	}
+}
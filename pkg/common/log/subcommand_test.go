@@ -0,0 +1,35 @@
+package log
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSubCommandStackJSON(t *testing.T) {
+	setFormatter("json")
+	t.Cleanup(func() { setFormatter("text") })
+
+	PushSubCommand("deploy")
+	PushSubCommand("validate")
+	nested := CaptureOutput(func() { Logger().Info("checking") })
+	PopSubCommand()
+	PopSubCommand()
+	empty := CaptureOutput(func() { Logger().Info("done") })
+
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(nested), &got))
+	assert.Equal(t, []interface{}{"deploy", "validate"}, got[SubCommandStackField])
+	assert.Equal(t, "deploy validate", got[SubCommandField])
+
+	got = nil
+	require.NoError(t, json.Unmarshal([]byte(empty), &got))
+	assert.NotContains(t, got, SubCommandStackField)
+	assert.NotContains(t, got, SubCommandField)
+}
+
+func TestPopSubCommandEmpty(t *testing.T) {
+	assert.NotPanics(t, PopSubCommand)
+	assert.Empty(t, SubCommands())
+}
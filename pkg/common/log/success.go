@@ -0,0 +1,35 @@
+package log
+
+import (
+	"github.com/sirupsen/logrus"
+	"regexp"
+	"sync"
+)
+
+var (
+	successMu       sync.RWMutex
+	successPatterns []*regexp.Regexp
+)
+
+// AddSuccessPattern renders info messages matching re entirely in green in the text format, e.g.
+// regexp.MustCompile(`(?i)\b(done|installed successfully)\b`) to highlight completed steps
+func AddSuccessPattern(re *regexp.Regexp) {
+	successMu.Lock()
+	defer successMu.Unlock()
+	successPatterns = append(successPatterns, re)
+}
+
+// isSuccess reports whether the entry is an info message matching a success pattern
+func isSuccess(entry *logrus.Entry) bool {
+	if entry.Level != logrus.InfoLevel {
+		return false
+	}
+	successMu.RLock()
+	defer successMu.RUnlock()
+	for _, re := range successPatterns {
+		if re.MatchString(entry.Message) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package log
+
+import (
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+)
+
+func TestAddSuccessPattern(t *testing.T) {
+	setFormatter("text")
+	noColor := color.NoColor
+	color.NoColor = false
+	t.Cleanup(func() {
+		color.NoColor = noColor
+		successPatterns = nil
+	})
+	AddSuccessPattern(regexp.MustCompile(`(?i)\bdone\b`))
+	AddSuccessPattern(regexp.MustCompile(`installed successfully`))
+
+	tests := []struct {
+		name string
+		log  func()
+		want string
+	}{
+		{"first pattern", func() { Logger().Info("Done") }, colorInfo("INFO") + ": " + colorInfo("Done") + "\n"},
+		{"second pattern", func() { Logger().Info("git installed successfully\n") }, colorInfo("INFO") + ": " + colorInfo("git installed successfully") + "\n"},
+		{"no match", func() { Logger().Info("downloading git") }, colorInfo("INFO") + ": downloading git\n"},
+		{"not info", func() { Logger().Warn("done with warnings") }, colorWarn("WARNING") + ": done with warnings\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CaptureOutput(tt.log))
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package log
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"runtime"
+	"strings"
+)
+
+var ( // For Test Mocks
+	lookupOSBuild = osBuild
+	numCPU        = runtime.NumCPU
+)
+
+// LogSystemInfo logs a diagnostic summary of the OS, architecture, Go version, hostname, number of
+// CPUs and on windows the OS build at info level, with each value as a field too
+func LogSystemInfo() {
+	host, _ := hostname()
+	fields := logrus.Fields{
+		"os":         runtime.GOOS,
+		"arch":       runtime.GOARCH,
+		"go_version": runtime.Version(),
+		"hostname":   host,
+		"num_cpu":    numCPU(),
+	}
+	platform := runtime.GOOS + "/" + runtime.GOARCH
+	if build := lookupOSBuild(); build != "" {
+		fields["os_build"] = build
+		platform += " build " + build
+	}
+
+	summary := []string{
+		platform,
+		runtime.Version(),
+		fmt.Sprintf("%d %s", numCPU(), pluralize(numCPU(), "CPU", "CPUs")),
+		"host " + host,
+	}
+	Logger().WithFields(fields).Infof("system: %s", strings.Join(summary, ", "))
+}
@@ -0,0 +1,8 @@
+// +build !windows
+
+package log
+
+// osBuild returns nothing, the OS build is only looked up on windows
+func osBuild() string {
+	return ""
+}
@@ -0,0 +1,48 @@
+package log
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"runtime"
+	"testing"
+)
+
+// stubSystemInfo replaces the platform specific lookups until the test finishes
+func stubSystemInfo(t *testing.T, build string) {
+	osBuildFunc, numCPUFunc, hostnameFunc := lookupOSBuild, numCPU, hostname
+	lookupOSBuild = func() string { return build }
+	numCPU = func() int { return 8 }
+	hostname = func() (string, error) { return "build-01", nil }
+	t.Cleanup(func() {
+		lookupOSBuild, numCPU, hostname = osBuildFunc, numCPUFunc, hostnameFunc
+	})
+}
+
+func TestLogSystemInfo(t *testing.T) {
+	setFormatter("text")
+	stubSystemInfo(t, "10.0.19043")
+
+	entries := CaptureEntries(LogSystemInfo)
+	require.Len(t, entries, 1)
+	assert.Equal(t, logrus.Fields{
+		"os":         runtime.GOOS,
+		"arch":       runtime.GOARCH,
+		"go_version": runtime.Version(),
+		"hostname":   "build-01",
+		"num_cpu":    8,
+		"os_build":   "10.0.19043",
+	}, entries[0].Data)
+	assert.Equal(t, "system: "+runtime.GOOS+"/"+runtime.GOARCH+" build 10.0.19043, "+runtime.Version()+", 8 CPUs, host build-01", entries[0].Message)
+}
+
+func TestLogSystemInfoWithoutBuild(t *testing.T) {
+	setFormatter("text")
+	stubSystemInfo(t, "")
+
+	out := CaptureOutput(LogSystemInfo)
+	assert.Equal(t, "INFO: system: "+runtime.GOOS+"/"+runtime.GOARCH+", "+runtime.Version()+", 8 CPUs, host build-01\n", out)
+	entries := CaptureEntries(LogSystemInfo)
+	require.Len(t, entries, 1)
+	assert.NotContains(t, entries[0].Data, "os_build")
+}
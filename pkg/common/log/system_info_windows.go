@@ -0,0 +1,14 @@
+// +build windows
+
+package log
+
+import (
+	"fmt"
+	"golang.org/x/sys/windows"
+)
+
+// osBuild returns the windows version and build number, e.g. 10.0.19043
+func osBuild() string {
+	info := windows.RtlGetVersion()
+	return fmt.Sprintf("%d.%d.%d", info.MajorVersion, info.MinorVersion, info.BuildNumber)
+}
@@ -0,0 +1,102 @@
+package log
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// maxFields is the maximum number of fields rendered by the text format, 0 renders all fields
+var maxFields int32
+
+// priorityFields are rendered before all other fields, which follow sorted by name
+var priorityFields = []string{logrus.ErrorKey}
+
+var showFields int32
+
+// SetShowFields renders the fields of every entry as key=value pairs after the message in the text
+// format, for the output and every text sink
+func SetShowFields(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&showFields, v)
+}
+
+// showFieldsEnabled reports whether SetShowFields turned the fields on
+func showFieldsEnabled() bool {
+	return atomic.LoadInt32(&showFields) == 1
+}
+
+// SetMaxFields limits the text format to render at most n fields followed by a "(+K more)" note,
+// the JSON format keeps all fields. 0 means unlimited
+func SetMaxFields(n int) {
+	if n < 0 {
+		n = 0
+	}
+	atomic.StoreInt32(&maxFields, int32(n))
+}
+
+// renderFields renders the fields as " key=value" pairs for the text format
+func renderFields(fields logrus.Fields) string {
+	keys := sortedFieldKeys(fields)
+	limit := int(atomic.LoadInt32(&maxFields))
+
+	var b strings.Builder
+	for i, k := range keys {
+		if limit > 0 && i == limit {
+			fmt.Fprintf(&b, " (+%d more)", len(keys)-limit)
+			break
+		}
+		b.WriteByte(' ')
+		b.WriteString(k)
+		b.WriteByte('=')
+		b.WriteString(formatFieldValue(fields[k]))
+	}
+	return b.String()
+}
+
+// sortedFieldKeys returns the priority fields present followed by the remaining keys sorted by name
+func sortedFieldKeys(fields logrus.Fields) []string {
+	keys := make([]string, 0, len(fields))
+	for _, k := range priorityFields {
+		if _, ok := fields[k]; ok {
+			keys = append(keys, k)
+		}
+	}
+	rest := make([]string, 0, len(fields))
+	for k := range fields {
+		if !isPriorityField(k) && !isUnitField(fields, k) {
+			rest = append(rest, k)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
+
+func isPriorityField(key string) bool {
+	for _, k := range priorityFields {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// formatFieldValue renders a field value, quoting it when it would be ambiguous unquoted
+func formatFieldValue(value interface{}) string {
+	var s string
+	if err, ok := value.(error); ok {
+		s = err.Error()
+	} else {
+		s = fmt.Sprint(value)
+	}
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,72 @@
+package log
+
+import (
+	"errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetShowFields(t *testing.T) {
+	setFormatter("text")
+	t.Cleanup(func() { SetShowFields(false) })
+
+	out := CaptureOutput(func() { Logger().WithField("package", "git").Info("installing") })
+	assert.Equal(t, "INFO: installing\n", out)
+
+	SetShowFields(true)
+	out = CaptureOutput(func() { Logger().WithField("package", "git").Info("installing") })
+	assert.Equal(t, "INFO: installing package=git\n", out)
+}
+
+func TestSetMaxFields(t *testing.T) {
+	logrus.SetFormatter(&CustomTextFormat{ShowFields: true})
+	t.Cleanup(func() {
+		SetMaxFields(0)
+		setFormatter("text")
+	})
+
+	fields := logrus.Fields{"e": 5, "d": 4, "c": 3, "b": 2, "a": 1}
+	tests := []struct {
+		name string
+		max  int
+		want string
+	}{
+		{"unlimited", 0, "INFO: installing a=1 b=2 c=3 d=4 e=5\n"},
+		{"truncated", 2, "INFO: installing a=1 b=2 (+3 more)\n"},
+		{"exact", 5, "INFO: installing a=1 b=2 c=3 d=4 e=5\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetMaxFields(tt.max)
+			out := CaptureOutput(func() { Logger().WithFields(fields).Info("installing") })
+			assert.Equal(t, tt.want, out)
+		})
+	}
+
+	setFormatter("json")
+	out := CaptureOutput(func() { Logger().WithFields(fields).Info("installing") })
+	assert.Contains(t, out, `"e":5`)
+}
+
+func TestRenderFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields logrus.Fields
+		want   string
+	}{
+		{"error first", logrus.Fields{"a": 1, logrus.ErrorKey: errors.New("boom")}, " error=boom a=1"},
+		{"quoted", logrus.Fields{"path": "C:\\Program Files", "empty": ""}, ` empty="" path="C:\\Program Files"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, renderFields(tt.fields))
+		})
+	}
+}
+
+func TestCustomTextFormatHidesFields(t *testing.T) {
+	setFormatter("text")
+	out := CaptureOutput(func() { Logger().WithField("a", 1).Info("installing") })
+	assert.Equal(t, "INFO: installing\n", out)
+}
@@ -0,0 +1,73 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// unitSuffix is appended to the key of a field holding the unit of its sibling
+const unitSuffix = "_unit"
+
+var byteUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// byteSize is a byte count rendered human readable in text and as the raw number in JSON
+type byteSize int64
+
+func (b byteSize) String() string {
+	value := float64(b)
+	unit := 0
+	for (value >= 1024 || value <= -1024) && unit < len(byteUnits)-1 {
+		value /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%d %s", int64(b), byteUnits[0])
+	}
+	s := strings.TrimSuffix(strconv.FormatFloat(value, 'f', 1, 64), ".0")
+	return s + " " + byteUnits[unit]
+}
+
+// durationValue is a duration rendered as e.g. 2m3s in text and as the number of seconds in JSON
+type durationValue time.Duration
+
+func (d durationValue) String() string {
+	return time.Duration(d).String()
+}
+
+func (d durationValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).Seconds())
+}
+
+// WithBytes returns an entry with the byte count n as the field key, rendered as e.g. "1.5 GiB" in
+// text while JSON holds the raw number with a key_unit field of "bytes"
+func WithBytes(key string, n int64) *logrus.Entry {
+	return Logger().WithFields(logrus.Fields{
+		key:              byteSize(n),
+		key + unitSuffix: "bytes",
+	})
+}
+
+// WithDuration returns an entry with the duration d as the field key, rendered as e.g. "2m3s" in
+// text while JSON holds the number of seconds with a key_unit field of "s"
+func WithDuration(key string, d time.Duration) *logrus.Entry {
+	return Logger().WithFields(logrus.Fields{
+		key:              durationValue(d),
+		key + unitSuffix: "s",
+	})
+}
+
+// isUnitField reports whether key holds the unit of a sibling field, which text output already renders
+func isUnitField(fields logrus.Fields, key string) bool {
+	if !strings.HasSuffix(key, unitSuffix) {
+		return false
+	}
+	switch fields[strings.TrimSuffix(key, unitSuffix)].(type) {
+	case byteSize, durationValue:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package log
+
+import (
+	"encoding/json"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestWithBytesAndDurationText(t *testing.T) {
+	logrus.SetFormatter(&CustomTextFormat{ShowFields: true})
+	t.Cleanup(func() { setFormatter("text") })
+
+	out := CaptureOutput(func() {
+		WithBytes("size", 1610612736).Info("downloaded")
+		WithDuration("took", 123*time.Second).Info("installed")
+	})
+	assert.Equal(t, "INFO: downloaded size=\"1.5 GiB\"\nINFO: installed took=2m3s\n", out)
+}
+
+func TestWithBytesAndDurationJSON(t *testing.T) {
+	setFormatter("json")
+	t.Cleanup(func() { setFormatter("text") })
+
+	out := CaptureOutput(func() {
+		WithBytes("size", 1610612736).WithField("other", 1).Info("downloaded")
+	})
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(out), &got))
+	assert.Equal(t, float64(1610612736), got["size"])
+	assert.Equal(t, "bytes", got["size_unit"])
+
+	out = CaptureOutput(func() { WithDuration("took", 1500*time.Millisecond).Info("installed") })
+	got = nil
+	require.NoError(t, json.Unmarshal([]byte(out), &got))
+	assert.Equal(t, 1.5, got["took"])
+	assert.Equal(t, "s", got["took_unit"])
+}
+
+func TestByteSize_String(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{512, "512 B"},
+		{1024, "1 KiB"},
+		{1536, "1.5 KiB"},
+		{5 * 1024 * 1024, "5 MiB"},
+		{1610612736, "1.5 GiB"},
+		{-2048, "-2 KiB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, byteSize(tt.n).String())
+		})
+	}
+}
+
+func TestIsUnitField(t *testing.T) {
+	fields := logrus.Fields{"size": byteSize(1), "size_unit": "bytes", "temp_unit": "celsius", "temp": 20}
+	assert.True(t, isUnitField(fields, "size_unit"))
+	assert.False(t, isUnitField(fields, "temp_unit"))
+	assert.False(t, isUnitField(fields, "size"))
+}
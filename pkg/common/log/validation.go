@@ -0,0 +1,24 @@
+package log
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+// LogValidation logs the result of a prerequisite check, "✓ check" in green at info level when it
+// passed or "✗ check: detail" in red at error level when it failed, with check and passed fields
+func LogValidation(check string, passed bool, detail string) {
+	entry := Logger().WithFields(logrus.Fields{
+		"check":  check,
+		"passed": passed,
+	})
+	if passed {
+		withStyle(entry, colorStyle(colorInfo)).Info("✓ " + check)
+		return
+	}
+	msg := "✗ " + check
+	if detail != "" {
+		entry = entry.WithField("detail", detail)
+		msg += ": " + detail
+	}
+	withStyle(entry, colorStyle(colorError)).Error(msg)
+}
@@ -0,0 +1,80 @@
+package log
+
+import (
+	"bytes"
+	"github.com/fatih/color"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestLogValidation(t *testing.T) {
+	setFormatter("text")
+	noColor := color.NoColor
+	color.NoColor = false
+	t.Cleanup(func() { color.NoColor = noColor })
+
+	tests := []struct {
+		name   string
+		check  string
+		passed bool
+		detail string
+		want   string
+	}{
+		{"pass", "git installed", true, "", colorInfo("INFO") + ": " + colorInfo("✓ git installed") + "\n"},
+		{"fail without detail", "admin rights", false, "", colorError("ERROR") + ": " + colorError("✗ admin rights") + "\n"},
+		{"fail", "disk space", false, "2GB free, 10GB required", colorError("ERROR") + ": " + colorError("✗ disk space: 2GB free, 10GB required") + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := CaptureOutput(func() { LogValidation(tt.check, tt.passed, tt.detail) })
+			assert.Equal(t, tt.want, out)
+		})
+	}
+}
+
+func TestLogValidationFields(t *testing.T) {
+	setFormatter("json")
+	t.Cleanup(func() { setFormatter("text") })
+
+	entries := CaptureEntries(func() {
+		LogValidation("git installed", true, "")
+		LogValidation("disk space", false, "2GB free")
+	})
+	require.Len(t, entries, 2)
+	assert.Equal(t, logrus.Fields{"check": "git installed", "passed": true}, entries[0].Data)
+	assert.Equal(t, "✓ git installed", entries[0].Message)
+	assert.Equal(t, logrus.ErrorLevel, entries[1].Level)
+	assert.Equal(t, logrus.Fields{"check": "disk space", "passed": false, "detail": "2GB free"}, entries[1].Data)
+	assert.Equal(t, "✗ disk space: 2GB free", entries[1].Message)
+}
+
+func TestStyledMessagesStayPlain(t *testing.T) {
+	restoreHooks(t)
+	setFormatter("text")
+	noColor := color.NoColor
+	color.NoColor = false
+	t.Cleanup(func() { color.NoColor = noColor })
+
+	audit := &bytes.Buffer{}
+	AddSink("audit", audit, "json")
+	var out string
+	entries := CaptureEntries(func() {
+		out = CaptureOutput(func() {
+			LogValidation("git installed", true, "")
+			LogSkip("install git", "already installed")
+			LogResult("disk space", errors.New("2GB free"))
+		})
+	})
+
+	assert.Contains(t, out, colorInfo("✓ git installed"))
+	assert.Contains(t, out, colorMuted("⊘ skipped install git (already installed)"))
+	assert.Contains(t, out, colorError("FAIL disk space: 2GB free"))
+	assert.NotContains(t, audit.String(), "\x1b")
+	require.Len(t, entries, 3)
+	for _, entry := range entries {
+		assert.NotContains(t, entry.Message, "\x1b")
+	}
+}
@@ -0,0 +1,51 @@
+package log
+
+import (
+	"encoding/json"
+	"github.com/sirupsen/logrus"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ansiEscape matches the color escape sequences written by the text format
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// WrappedJSONFormatter renders every entry as a JSON object holding the plain text line under
+// "rendered" along with its level and time, for pipelines wanting JSON around human readable text
+type WrappedJSONFormatter struct {
+	Text *CustomTextFormat
+}
+
+// wrappedRecord is the JSON object written per entry
+type wrappedRecord struct {
+	Rendered string `json:"rendered"`
+	Level    string `json:"level"`
+	Time     string `json:"time"`
+}
+
+// NewWrappedJSONFormatter returns a WrappedJSONFormatter rendering the text with its fields
+func NewWrappedJSONFormatter() *WrappedJSONFormatter {
+	text := NewCustomTextFormat()
+	text.ShowFields = true
+	return &WrappedJSONFormatter{Text: text}
+}
+
+// Format formats the log statement
+func (f *WrappedJSONFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	e := *entry
+	e.Buffer = nil
+	rendered, err := f.Text.Format(&e)
+	if err != nil {
+		return nil, err
+	}
+	serialized, err := json.Marshal(wrappedRecord{
+		Rendered: strings.TrimSuffix(ansiEscape.ReplaceAllString(string(rendered), ""), "\n"),
+		Level:    entry.Level.String(),
+		Time:     entry.Time.Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(serialized, '\n'), nil
+}
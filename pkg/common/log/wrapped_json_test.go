@@ -0,0 +1,59 @@
+package log
+
+import (
+	"encoding/json"
+	"github.com/fatih/color"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrappedJSONFormatter(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	t.Cleanup(func() {
+		color.NoColor = noColor
+		setFormatter("text")
+	})
+	setFormatter("json-wrapped")
+	useFakeClock(t)
+
+	out := CaptureOutput(func() {
+		Logger().WithTime(now()).WithField("package", "git").Warn("did thing")
+		LogValidation("disk space", false, "")
+	})
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	require.Len(t, lines, 2)
+
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &got))
+	assert.Equal(t, map[string]interface{}{
+		"rendered": "WARNING: did thing package=git",
+		"level":    "warning",
+		"time":     "2021-08-26T12:00:00Z",
+	}, got)
+
+	got = nil
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &got))
+	assert.Equal(t, "ERROR: ✗ disk space check=\"disk space\" passed=false", got["rendered"])
+	assert.Equal(t, "error", got["level"])
+	assert.Contains(t, got, "time")
+}
+
+func TestWrappedJSONFormatterStripsColor(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	t.Cleanup(func() { color.NoColor = noColor })
+
+	entry := logrus.NewEntry(logrus.StandardLogger())
+	entry.Level = logrus.InfoLevel
+	entry.Time = time.Date(2021, 8, 26, 12, 0, 0, 0, time.UTC)
+	entry.Message = colorInfo("✓ done")
+
+	serialized, err := NewWrappedJSONFormatter().Format(entry)
+	require.NoError(t, err)
+	assert.Equal(t, `{"rendered":"INFO: ✓ done","level":"info","time":"2021-08-26T12:00:00Z"}`+"\n", string(serialized))
+}